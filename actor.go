@@ -22,6 +22,9 @@ const (
 	MusicbrainzAlbumArtistID
 	MusicbrainzArtistID
 	MusicbrainzOriginalArtistID
+	DiscogsArtistName
+	ISNI
+	IPI
 )
 
 // StrToActorID ..
@@ -30,6 +33,9 @@ var StrToActorID = map[string]ActorID{
 	"musicbrainz_album_artist_id":    MusicbrainzAlbumArtistID,
 	"musicbrainz_artist_id":          MusicbrainzArtistID,
 	"musicbrainz_original_artist_id": MusicbrainzOriginalArtistID,
+	"discogs_artist_name":            DiscogsArtistName,
+	"isni":                           ISNI,
+	"ipi":                            IPI,
 }
 
 func (aid ActorID) String() string {
@@ -42,8 +48,25 @@ func (aid ActorID) String() string {
 		return "musicbrainz_artist_id"
 	case MusicbrainzOriginalArtistID:
 		return "musicbrainz_original_artist_id"
+	case DiscogsArtistName:
+		return "discogs_artist_name"
+	case ISNI:
+		return "isni"
+	case IPI:
+		return "ipi"
 	}
-	return ""
+	return registeredIDKey(EntityArtist, uint8(aid))
+}
+
+// MarshalText позволяет использовать ActorID в качестве ключа словаря JSON.
+func (aid ActorID) MarshalText() ([]byte, error) {
+	return []byte(aid.String()), nil
+}
+
+// UnmarshalText получает ActorID из ключа словаря JSON (см. parseIDKey).
+func (aid *ActorID) UnmarshalText(b []byte) error {
+	*aid = ActorID(parseIDKey(EntityArtist, string(b)))
+	return nil
 }
 
 // ActorIDs представляет словарь идентификаторов акторов во внешних БД.
@@ -66,7 +89,7 @@ func (aid *ActorIDs) UnmarshalJSON(b []byte) error {
 	}
 	*aid = make(ActorIDs, len(x))
 	for k, v := range x {
-		(*aid)[StrToActorID[k]] = v
+		(*aid)[ActorID(LookupID(EntityArtist, k))] = v
 	}
 	return nil
 }
@@ -78,21 +101,38 @@ type ActorsIDs map[ActorName]ActorIDs
 // композиции, записи и т.д.).
 type ActorRoles map[ActorName][]ActorRole
 
-// IsPerformer предикатная функция фильтрации исполнителей альбома.
-func IsPerformer(name ActorName, roles []ActorRole) bool {
-	return collection.ContainsStr("performer", roles)
-}
-
 // Add добавляет сведеления об акторе и его коде во некоторой внешней БД, если необходимо.
+// Имя актора сопоставляется с уже существующими именами коллекции по нормализованному виду
+// (см. SameActor), а суффикс уточнения Discogs вида "(3)" сохраняется как идентификатор
+// DiscogsArtistName. Новый актор добавляется под переданным именем.
 func (ai ActorsIDs) Add(name ActorName, key ActorID, val string) {
+	name, discogsName := SplitDiscogsName(name)
+	name = ai.Name(name)
 	ids, ok := ai[name]
 	if !ok {
-		ai[name] = map[ActorID]string{key: val}
-	} else {
-		if _, ok := ids[key]; !ok {
-			ai[name][key] = val
+		ids = ActorIDs{}
+		ai[name] = ids
+	}
+	if _, ok := ids[key]; !ok {
+		ids[key] = val
+	}
+	if _, ok := ids[DiscogsArtistName]; !ok && discogsName != "" {
+		ids[DiscogsArtistName] = discogsName
+	}
+}
+
+// Name возвращает имя актора, под которым он уже присутствует в коллекции, или
+// переданное имя, если актор в коллекции отсутствует.
+func (ai ActorsIDs) Name(name ActorName) ActorName {
+	if _, ok := ai[name]; ok {
+		return name
+	}
+	for actorName := range ai {
+		if SameActor(name, actorName) {
+			return actorName
 		}
 	}
+	return name
 }
 
 // Merge объединяет данные в целевой исходный объект.
@@ -127,7 +167,7 @@ func (ai ActorsIDs) Clean() {
 }
 
 // Compare сравнивает объект с аналогичным и определяет его степень схожести в числовом
-// выражении.
+// выражении. Имена акторов сравниваются в нормализованном виде.
 func (ar ActorRoles) Compare(other ActorRoles) float64 {
 	if len(ar) == 0 || len(other) == 0 {
 		return 0.
@@ -135,7 +175,10 @@ func (ar ActorRoles) Compare(other ActorRoles) float64 {
 	var max, res float64
 	for name := range ar {
 		for otherName := range other {
-			res = stringutils.JaroWinklerDistance(string(name), string(otherName))
+			if SameActor(name, otherName) {
+				return 1.
+			}
+			res = stringutils.JaroWinklerDistance(ActorNameKey(name), ActorNameKey(otherName))
 			if max < res {
 				max = res
 			}
@@ -144,8 +187,10 @@ func (ar ActorRoles) Compare(other ActorRoles) float64 {
 	return max
 }
 
-// Add добавляет актора и роль, если необходимо.
+// Add добавляет актора и роль, если необходимо. Имя актора сопоставляется с уже
+// существующими именами коллекции так же, как в ActorsIDs.Add.
 func (ar ActorRoles) Add(name ActorName, role ActorRole) {
+	name = ar.Name(name)
 	roles := ar[name]
 	if !collection.ContainsStr(role, roles) {
 		roles = append(roles, role)
@@ -153,6 +198,45 @@ func (ar ActorRoles) Add(name ActorName, role ActorRole) {
 	ar[name] = roles
 }
 
+// Name возвращает имя актора, под которым он уже присутствует в коллекции, или
+// переданное имя без суффикса уточнения Discogs, если актор в коллекции отсутствует.
+func (ar ActorRoles) Name(name ActorName) ActorName {
+	name, _ = SplitDiscogsName(name)
+	if _, ok := ar[name]; ok {
+		return name
+	}
+	for actorName := range ar {
+		if SameActor(name, actorName) {
+			return actorName
+		}
+	}
+	return name
+}
+
+// Normalize приводит все роли акторов к каноническому виду и удаляет дубликаты ролей.
+func (ar ActorRoles) Normalize() {
+	for name, roles := range ar {
+		normalized := make([]ActorRole, 0, len(roles))
+		for _, role := range roles {
+			role = CanonicalRole(role)
+			if role != "" && !collection.ContainsStr(role, normalized) {
+				normalized = append(normalized, role)
+			}
+		}
+		ar[name] = normalized
+	}
+}
+
+// HasRole проверяет наличие у актора роли с учетом синонимов различных сервисов.
+func (ar ActorRoles) HasRole(name ActorName, role ActorRole) bool {
+	for _, r := range ar[name] {
+		if SameRole(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
 // Filter фильтрует коллекцию акторов с определенной функцией-предикатом.
 func (ar ActorRoles) Filter(predicat func(name ActorName, roles []ActorRole) bool) ActorRoles {
 	ret := ActorRoles{}
@@ -0,0 +1,265 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	collection "github.com/ytsiuryn/go-collection"
+)
+
+// ActorType описывает тип актора.
+type ActorType int8
+
+// Допустимые типы акторов.
+const (
+	ActorTypePerson ActorType = iota + 1
+	ActorTypeGroup
+	ActorTypeOrchestra
+	ActorTypeChoir
+)
+
+// StrToActorType ..
+var StrToActorType = map[string]ActorType{
+	"person":    ActorTypePerson,
+	"group":     ActorTypeGroup,
+	"orchestra": ActorTypeOrchestra,
+	"choir":     ActorTypeChoir,
+}
+
+func (at ActorType) String() string {
+	switch at {
+	case ActorTypePerson:
+		return "person"
+	case ActorTypeGroup:
+		return "group"
+	case ActorTypeOrchestra:
+		return "orchestra"
+	case ActorTypeChoir:
+		return "choir"
+	}
+	return ""
+}
+
+// MarshalJSON ..
+func (at ActorType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(at.String())
+}
+
+// UnmarshalJSON ..
+func (at *ActorType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*at = StrToActorType[s]
+	return nil
+}
+
+// LifeSpan описывает период жизни персоны или существования коллектива.
+// Даты указываются в виде "YYYY", "YYYY-MM" или "YYYY-MM-DD".
+type LifeSpan struct {
+	Begin string `json:"begin,omitempty"`
+	End   string `json:"end,omitempty"`
+	Ended bool   `json:"ended,omitempty"`
+}
+
+// Membership описывает участие персоны в коллективе.
+type Membership struct {
+	Name  ActorName   `json:"name"`
+	Roles []ActorRole `json:"roles,omitempty"`
+	Begin string      `json:"begin,omitempty"`
+	End   string      `json:"end,omitempty"`
+}
+
+// Actor описывает сведения об акторе, дополняющие его идентификаторы во внешних БД.
+// Имя актора является ключом коллекции ActorsInfo, а идентификаторы хранятся
+// в коллекции ActorsIDs под тем же именем.
+type Actor struct {
+	Type       ActorType     `json:"type,omitempty"`
+	SortName   string        `json:"sort_name,omitempty"`
+	Aliases    []ActorName   `json:"aliases,omitempty"`
+	Variations []ActorName   `json:"variations,omitempty"` // Discogs ANV
+	Members    []*Membership `json:"members,omitempty"`
+	Country    string        `json:"country,omitempty"`
+	LifeSpan   *LifeSpan     `json:"life_span,omitempty"`
+}
+
+// NewActor создает новый объект Actor.
+func NewActor(actorType ActorType) *Actor {
+	return &Actor{Type: actorType}
+}
+
+// AddAlias добавляет псевдоним актора, если необходимо.
+func (a *Actor) AddAlias(alias ActorName) {
+	if !collection.ContainsStr(alias, a.Aliases) {
+		a.Aliases = append(a.Aliases, alias)
+	}
+}
+
+// AddVariation добавляет вариант написания имени актора, если необходимо.
+func (a *Actor) AddVariation(anv ActorName) {
+	if !collection.ContainsStr(anv, a.Variations) {
+		a.Variations = append(a.Variations, anv)
+	}
+}
+
+// Copy возвращает копию сведений об участии персоны в коллективе.
+func (m *Membership) Copy() *Membership {
+	ret := *m
+	ret.Roles = append([]ActorRole(nil), m.Roles...)
+	return &ret
+}
+
+// AddMember добавляет копию сведений об участнике коллектива. Повторное участие одной
+// и той же персоны допустимо, если оно относится к другому периоду.
+func (a *Actor) AddMember(m *Membership) {
+	for _, member := range a.Members {
+		if member.Name == m.Name && member.Begin == m.Begin {
+			for _, role := range m.Roles {
+				if !collection.ContainsStr(role, member.Roles) {
+					member.Roles = append(member.Roles, role)
+				}
+			}
+			return
+		}
+	}
+	a.Members = append(a.Members, m.Copy())
+}
+
+// IsGroup проверяет, является ли актор коллективом.
+func (a *Actor) IsGroup() bool {
+	return a.Type == ActorTypeGroup || a.Type == ActorTypeOrchestra || a.Type == ActorTypeChoir
+}
+
+// Copy возвращает копию сведений об акторе, не разделяющую с исходным объектом
+// изменяемые данные.
+func (a *Actor) Copy() *Actor {
+	ret := &Actor{
+		Type:       a.Type,
+		SortName:   a.SortName,
+		Aliases:    append([]ActorName(nil), a.Aliases...),
+		Variations: append([]ActorName(nil), a.Variations...),
+		Country:    a.Country,
+	}
+	if a.LifeSpan != nil {
+		lifeSpan := *a.LifeSpan
+		ret.LifeSpan = &lifeSpan
+	}
+	for _, m := range a.Members {
+		ret.Members = append(ret.Members, m.Copy())
+	}
+	return ret
+}
+
+// Merge дополняет объект незаполненными сведениями из другого объекта.
+func (a *Actor) Merge(other *Actor) {
+	if a.Type == 0 {
+		a.Type = other.Type
+	}
+	if a.SortName == "" {
+		a.SortName = other.SortName
+	}
+	if a.Country == "" {
+		a.Country = other.Country
+	}
+	if a.LifeSpan == nil && other.LifeSpan != nil {
+		lifeSpan := *other.LifeSpan
+		a.LifeSpan = &lifeSpan
+	}
+	for _, alias := range other.Aliases {
+		a.AddAlias(alias)
+	}
+	for _, anv := range other.Variations {
+		a.AddVariation(anv)
+	}
+	for _, m := range other.Members {
+		a.AddMember(m)
+	}
+}
+
+// ActorsInfo хранит сведения об акторах по их именам.
+type ActorsInfo map[ActorName]*Actor
+
+// Add добавляет копию сведений об акторе или дополняет уже существующие.
+func (ai ActorsInfo) Add(name ActorName, actor *Actor) {
+	if old, ok := ai[name]; ok {
+		old.Merge(actor)
+		return
+	}
+	ai[name] = actor.Copy()
+}
+
+// Merge объединяет данные в целевой исходный объект.
+func (ai ActorsInfo) Merge(other ActorsInfo) {
+	for name, actor := range other {
+		ai.Add(name, actor)
+	}
+}
+
+// ByVariation возвращает имя актора по его псевдониму или варианту написания имени.
+func (ai ActorsInfo) ByVariation(anv ActorName) ActorName {
+	for name, actor := range ai {
+		if collection.ContainsStr(anv, actor.Variations) ||
+			collection.ContainsStr(anv, actor.Aliases) {
+			return name
+		}
+	}
+	return ""
+}
+
+// IsEmpty проверяет коллекцию на пустоту.
+func (ai ActorsInfo) IsEmpty() bool {
+	return len(ai) == 0
+}
+
+// NormalizeISNI проверяет контрольную сумму кода ISNI (ISO 27729) и возвращает его
+// в виде 16 символов без пробелов.
+func NormalizeISNI(isni string) (string, error) {
+	isni = strings.ToUpper(strings.Join(strings.Fields(isni), ""))
+	isni = strings.TrimPrefix(isni, "ISNI")
+	if len(isni) != 16 {
+		return "", fmt.Errorf("ISNI %q: wrong length", isni)
+	}
+	sum := 0
+	for _, r := range isni[:15] {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("ISNI %q: wrong character %q", isni, r)
+		}
+		sum = (sum + int(r-'0')) * 2
+	}
+	check := (12 - sum%11) % 11
+	expected := byte('0' + check)
+	if check == 10 {
+		expected = 'X'
+	}
+	if isni[15] != expected {
+		return "", fmt.Errorf("ISNI %q: wrong check digit", isni)
+	}
+	return isni, nil
+}
+
+// FormatISNI возвращает код ISNI в каноническом виде "0000 0001 2103 2683".
+func FormatISNI(isni string) string {
+	isni, err := NormalizeISNI(isni)
+	if err != nil {
+		return ""
+	}
+	return strings.Join([]string{isni[:4], isni[4:8], isni[8:12], isni[12:]}, " ")
+}
+
+// NormalizeIPI проверяет код IPI (Interested Parties Information) и возвращает его
+// в виде 11 цифр с ведущими нулями.
+func NormalizeIPI(ipi string) (string, error) {
+	ipi = strings.Join(strings.Fields(ipi), "")
+	ipi = strings.ReplaceAll(ipi, ".", "")
+	if len(ipi) == 0 || len(ipi) > 11 {
+		return "", fmt.Errorf("IPI %q: wrong length", ipi)
+	}
+	for _, r := range ipi {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("IPI %q: wrong character %q", ipi, r)
+		}
+	}
+	return strings.Repeat("0", 11-len(ipi)) + ipi, nil
+}
@@ -0,0 +1,102 @@
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorTypeMarshalAndUnmarshal(t *testing.T) {
+	data, err := json.Marshal(ActorType(0))
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`""`), data)
+	at := ActorTypeOrchestra
+	data, err = json.Marshal(at)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &at))
+	assert.Equal(t, ActorTypeOrchestra, at)
+}
+
+func TestActorMerge(t *testing.T) {
+	a := NewActor(ActorTypeGroup)
+	a.AddMember(&Membership{Name: "John Lennon", Roles: []ActorRole{"guitar"}, Begin: "1960"})
+	other := &Actor{SortName: "Beatles, The", Country: "GB", Variations: []ActorName{"Beatles"}}
+	other.AddMember(&Membership{Name: "John Lennon", Roles: []ActorRole{"vocals"}, Begin: "1960"})
+	other.AddMember(&Membership{Name: "Ringo Starr", Begin: "1962", End: "1970"})
+	a.Merge(other)
+	assert.True(t, a.IsGroup())
+	assert.Equal(t, "Beatles, The", a.SortName)
+	assert.Len(t, a.Members, 2)
+	assert.Equal(t, []ActorRole{"guitar", "vocals"}, a.Members[0].Roles)
+}
+
+func TestActorsInfoAddAndByVariation(t *testing.T) {
+	ai := ActorsInfo{}
+	ai.Add("The Beatles", &Actor{Type: ActorTypeGroup})
+	ai.Add("The Beatles", &Actor{Variations: []ActorName{"Beatles"}})
+	assert.Len(t, ai, 1)
+	assert.Equal(t, ActorTypeGroup, ai["The Beatles"].Type)
+	assert.Equal(t, "The Beatles", ai.ByVariation("Beatles"))
+	assert.Empty(t, ai.ByVariation("Nemo"))
+}
+
+func TestActorsInfoAddCopies(t *testing.T) {
+	member := &Membership{Name: "John Lennon", Roles: []ActorRole{"guitar"}}
+	actor := &Actor{LifeSpan: &LifeSpan{Begin: "1960"}}
+	actor.AddMember(member)
+	member.Roles[0] = "bass"
+	assert.Equal(t, "guitar", actor.Members[0].Roles[0])
+
+	ai := ActorsInfo{}
+	ai.Add("The Beatles", actor)
+	ai["The Beatles"].LifeSpan.End = "1970"
+	ai["The Beatles"].Members[0].Roles = append(ai["The Beatles"].Members[0].Roles, "vocals")
+	assert.Empty(t, actor.LifeSpan.End)
+	assert.Len(t, actor.Members[0].Roles, 1)
+}
+
+func TestActorsInfoMarshal(t *testing.T) {
+	ai := ActorsInfo{"Miles Davis": {
+		Type: ActorTypePerson, LifeSpan: &LifeSpan{Begin: "1926-05-26", End: "1991-09-28", Ended: true}}}
+	data, err := json.Marshal(ai)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`{"Miles Davis":{"type":"person","life_span":{"begin":"1926-05-26","end":"1991-09-28","ended":true}}}`,
+		string(data))
+	other := ActorsInfo{}
+	require.NoError(t, json.Unmarshal(data, &other))
+	assert.Equal(t, ai, other)
+}
+
+func TestNormalizeISNI(t *testing.T) {
+	isni, err := NormalizeISNI("0000 0001 2103 2683")
+	require.NoError(t, err)
+	assert.Equal(t, "0000000121032683", isni)
+	_, err = NormalizeISNI("0000 0001 2103 2684")
+	assert.Error(t, err)
+	_, err = NormalizeISNI("0000 0001")
+	assert.Error(t, err)
+	assert.Equal(t, "0000 0001 2103 2683", FormatISNI("0000000121032683"))
+}
+
+func TestNormalizeIPI(t *testing.T) {
+	ipi, err := NormalizeIPI("254.34.23.26")
+	require.NoError(t, err)
+	assert.Equal(t, "00254342326", ipi)
+	_, err = NormalizeIPI("I-000.000.001-1")
+	assert.Error(t, err)
+}
+
+func TestEnumUnmarshalJSONRejectsNonString(t *testing.T) {
+	var at ActorType
+	require.NoError(t, json.Unmarshal([]byte(`"person"`), &at))
+	assert.Equal(t, ActorTypePerson, at)
+	for _, v := range []json.Unmarshaler{
+		new(ActorType), new(ChannelMode), new(DiscLayer), new(CompanyRole), new(NoticeType),
+		new(BarcodeType), new(Tonality), new(WorkRelationType), new(EntityKind)} {
+		assert.Error(t, v.UnmarshalJSON([]byte(`1`)), "%T", v)
+		assert.Error(t, v.UnmarshalJSON([]byte(`person`)), "%T", v)
+	}
+}
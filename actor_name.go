@@ -0,0 +1,199 @@
+package metadata
+
+import (
+	"strings"
+	"unicode"
+
+	collection "github.com/ytsiuryn/go-collection"
+)
+
+// Артикли по языкам (ISO 639-1), переносимые в конец имени коллектива при формировании
+// имени для сортировки. Артикли с апострофом ("l'") отделяются от следующего слова.
+var sortArticles = map[string][]string{
+	"en": {"the", "a", "an"},
+	"fr": {"le", "la", "les", "l'"},
+	"de": {"der", "die", "das"},
+	"es": {"el", "los", "las"},
+	"it": {"il", "lo", "gli", "i", "l'"},
+	"nl": {"de", "het", "een"},
+	"pt": {"os", "as"},
+}
+
+// Частицы фамилий персон.
+var nameParticles = []string{
+	"van", "von", "der", "den", "ter", "ten", "de", "da", "di", "del", "della", "degli",
+	"du", "des", "le", "la", "dos", "das", "do", "zu", "af", "av", "bin", "ibn", "al",
+}
+
+// Суффиксы имени персоны.
+var nameSuffixes = []string{"jr", "jr.", "sr", "sr.", "ii", "iii", "iv"}
+
+var diacriticsReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a", "æ", "ae",
+	"ç", "c", "č", "c", "ć", "c",
+	"é", "e", "è", "e", "ê", "e", "ë", "e", "ě", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ñ", "n", "ň", "n", "ń", "n",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o", "œ", "oe",
+	"ř", "r", "š", "s", "ś", "s", "ß", "ss",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u", "ů", "u",
+	"ý", "y", "ÿ", "y", "ž", "z", "ź", "z", "ż", "z", "ł", "l",
+)
+
+// SplitDiscogsName отделяет от имени актора Discogs суффикс уточнения вида "(3)".
+// Возвращает имя без суффикса и исходное имя Discogs, если суффикс присутствовал.
+func SplitDiscogsName(name string) (ActorName, string) {
+	name = strings.TrimSuffix(strings.TrimSpace(name), "*")
+	if discogsNameSuffix.MatchString(name) {
+		return discogsNameSuffix.ReplaceAllString(name, ""), name
+	}
+	return name, ""
+}
+
+// NormalizeActorName приводит имя актора к виду "Имя Фамилия": удаляет лишние пробелы
+// и суффикс уточнения Discogs, а также разворачивает инвертированные имена. Разворачиваются
+// только имена с фамилией из одного слова (возможно, с частицами) перед запятой ("Davis, Miles",
+// "Van Gelder, Rudy") и названия с артиклем после запятой ("Beatles, The"). Прочие имена
+// с запятой ("Tyler, The Creator", "Earth, Wind & Fire") не изменяются.
+func NormalizeActorName(name string) ActorName {
+	name, _ = SplitDiscogsName(strings.Join(strings.Fields(name), " "))
+	parts := strings.Split(name, ", ")
+	if len(parts) < 2 || len(parts) > 3 {
+		return name
+	}
+	if len(parts) == 2 && isAnyArticle(parts[1]) {
+		return parts[1] + " " + parts[0]
+	}
+	if !isInvertedSurname(parts[0]) {
+		return name
+	}
+	if len(parts) == 3 {
+		if !isNameSuffix(parts[2]) {
+			return name
+		}
+		return parts[1] + " " + parts[0] + " " + parts[2]
+	}
+	given := strings.Fields(strings.ToLower(parts[1]))
+	if len(given) > 3 || isNameSuffix(parts[1]) ||
+		(len(given[0]) > 1 && isAnyArticle(given[0])) ||
+		strings.ContainsAny(parts[1], "&+") || collection.ContainsStr("and", given) {
+		return name
+	}
+	return parts[1] + " " + parts[0]
+}
+
+// isInvertedSurname проверяет, может ли часть имени перед запятой быть фамилией:
+// одно слово, которому могут предшествовать частицы ("Van Gelder", "de la Cruz").
+func isInvertedSurname(s string) bool {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return false
+	}
+	for _, word := range words[:len(words)-1] {
+		if !isParticle(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// ActorNameKey возвращает ключ имени актора для сравнения: имя нормализуется, приводится
+// к нижнему регистру без диакритических знаков, пунктуации и ведущего артикля "the".
+func ActorNameKey(name string) string {
+	name = diacriticsReplacer.Replace(strings.ToLower(NormalizeActorName(name)))
+	flds := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	if len(flds) > 1 && flds[0] == "the" {
+		flds = flds[1:]
+	}
+	return strings.Join(flds, " ")
+}
+
+// SameActor проверяет, относятся ли два имени к одному актору.
+func SameActor(name1, name2 string) bool {
+	key := ActorNameKey(name1)
+	return key != "" && key == ActorNameKey(name2)
+}
+
+// SortName формирует имя актора для сортировки с учетом английских артиклей
+// (см. SortNameLang).
+func SortName(name ActorName, actorType ActorType) string {
+	return SortNameLang(name, actorType, "en")
+}
+
+// SortNameLang формирует имя актора для сортировки.
+// Для коллективов и акторов неизвестного типа ведущий артикль языка lang (ISO 639-1)
+// переносится в конец ("The Beatles" -> "Beatles, The"). Для персон фамилия выносится вперед, а частицы
+// фамилии в нижнем регистре и суффиксы остаются в конце ("Ludwig van Beethoven" ->
+// "Beethoven, Ludwig van"), а частицы с заглавной буквы считаются частью фамилии
+// ("Rudy Van Gelder" -> "Van Gelder, Rudy").
+func SortNameLang(name ActorName, actorType ActorType, lang string) string {
+	name = NormalizeActorName(name)
+	words := strings.Fields(name)
+	if len(words) < 2 {
+		return name
+	}
+	if actorType != ActorTypePerson {
+		if isArticle(words[0], lang) {
+			return strings.Join(words[1:], " ") + ", " + words[0]
+		}
+		if strings.HasPrefix(strings.ToLower(words[0]), "l'") && isArticle("l'", lang) {
+			return words[0][2:] + " " + strings.Join(words[1:], " ") + ", " + words[0][:2]
+		}
+		return name
+	}
+	var suffix string
+	if isNameSuffix(words[len(words)-1]) {
+		suffix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	first := len(words) - 1
+	for first > 1 && isParticle(words[first-1]) && unicode.IsUpper([]rune(words[first-1])[0]) {
+		first--
+	}
+	ret := strings.Join(words[first:], " ") + ", " + strings.Join(words[:first], " ")
+	if suffix != "" {
+		ret += ", " + suffix
+	}
+	return ret
+}
+
+func isArticle(word, lang string) bool {
+	word = strings.ToLower(word)
+	for _, article := range sortArticles[lang] {
+		if word == article {
+			return true
+		}
+	}
+	return false
+}
+
+func isAnyArticle(word string) bool {
+	for lang := range sortArticles {
+		if isArticle(word, lang) {
+			return true
+		}
+	}
+	return false
+}
+
+func isParticle(word string) bool {
+	word = strings.ToLower(word)
+	for _, particle := range nameParticles {
+		if word == particle {
+			return true
+		}
+	}
+	return false
+}
+
+func isNameSuffix(word string) bool {
+	word = strings.ToLower(word)
+	for _, suffix := range nameSuffixes {
+		if word == suffix {
+			return true
+		}
+	}
+	return false
+}
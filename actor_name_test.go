@@ -0,0 +1,68 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitDiscogsName(t *testing.T) {
+	name, discogsName := SplitDiscogsName("John Smith (3)")
+	assert.Equal(t, "John Smith", name)
+	assert.Equal(t, "John Smith (3)", discogsName)
+	name, discogsName = SplitDiscogsName("Davis*")
+	assert.Equal(t, "Davis", name)
+	assert.Empty(t, discogsName)
+}
+
+func TestNormalizeActorName(t *testing.T) {
+	results := map[string]string{
+		"Davis, Miles":          "Miles Davis",
+		"  Miles   Davis ":      "Miles Davis",
+		"Beatles, The":          "The Beatles",
+		"Beethoven, Ludwig van": "Ludwig van Beethoven",
+		"Davis, Sammy, Jr.":     "Sammy Davis Jr.",
+		"Earth, Wind & Fire":    "Earth, Wind & Fire",
+		"Peter, Paul and Mary":  "Peter, Paul and Mary",
+		"John Smith (3)":        "John Smith",
+		"Crosby, Stills, Nash":  "Crosby, Stills, Nash",
+		"Harry Connick, Jr.":    "Harry Connick, Jr.",
+		"Van Gelder, Rudy":      "Rudy Van Gelder",
+		"Mozart":                "Mozart",
+		"Tyler, The Creator":    "Tyler, The Creator",
+		"Smith, John Paul":      "John Paul Smith",
+		"Go West, Young Man":    "Go West, Young Man",
+		"Ärzte, Die":            "Die Ärzte",
+	}
+	for k, v := range results {
+		assert.Equal(t, v, NormalizeActorName(k), k)
+	}
+}
+
+func TestSameActor(t *testing.T) {
+	assert.True(t, SameActor("Davis, Miles", "Miles Davis"))
+	assert.True(t, SameActor("Beatles", "The Beatles"))
+	assert.True(t, SameActor("Antonín Dvořák", "Antonin Dvorak"))
+	assert.True(t, SameActor("John Smith (3)", "john smith"))
+	assert.False(t, SameActor("Miles Davis", "Miles Davies"))
+	assert.False(t, SameActor("", ""))
+}
+
+func TestSortName(t *testing.T) {
+	assert.Equal(t, "Beatles, The", SortName("The Beatles", ActorTypeGroup))
+	assert.Equal(t, "Beatles, The", SortName("The Beatles", 0))
+	assert.Equal(t, "Rolling Stones", SortName("Rolling Stones", ActorTypeGroup))
+	assert.Equal(t, "Orchestre de Paris, L'",
+		SortNameLang("L'Orchestre de Paris", ActorTypeOrchestra, "fr"))
+	assert.Equal(t, "Ärzte, Die", SortNameLang("Die Ärzte", ActorTypeGroup, "de"))
+	assert.Equal(t, "Die Ärzte", SortName("Die Ärzte", ActorTypeGroup))
+	assert.Equal(t, "De La Soul", SortName("De La Soul", ActorTypeGroup))
+	assert.Equal(t, "As I Lay Dying", SortName("As I Lay Dying", 0))
+	assert.Equal(t, "Tribe Called Quest, A", SortName("A Tribe Called Quest", ActorTypeGroup))
+	assert.Equal(t, "Davis, Miles", SortName("Miles Davis", ActorTypePerson))
+	assert.Equal(t, "Davis, Miles", SortName("Davis, Miles", ActorTypePerson))
+	assert.Equal(t, "Beethoven, Ludwig van", SortName("Ludwig van Beethoven", ActorTypePerson))
+	assert.Equal(t, "Van Gelder, Rudy", SortName("Rudy Van Gelder", ActorTypePerson))
+	assert.Equal(t, "Davis, Sammy, Jr.", SortName("Sammy Davis Jr.", ActorTypePerson))
+	assert.Equal(t, "Madonna", SortName("Madonna", ActorTypePerson))
+}
@@ -46,8 +46,28 @@ func TestActorRolesAdd(t *testing.T) {
 	ar.Add("John Doe", "conductor")
 	assert.Len(t, ar["John Doe"], 2)
 }
-func TestActorRolesCompare(t *testing.T) {
 
+func TestActorRolesAddNormalizesLikeActorsIDs(t *testing.T) {
+	ar := ActorRoles{}
+	ai := ActorsIDs{}
+	ar.Add("Davis, Miles (2)", "trumpet")
+	ai.Add("Davis, Miles (2)", DiscogsArtistID, "23755")
+	assert.Contains(t, ar, "Davis, Miles")
+	assert.Contains(t, ai, "Davis, Miles")
+	ar.Add("Miles Davis", "composer")
+	assert.Len(t, ar, 1)
+	assert.Equal(t, []ActorRole{"trumpet", "composer"}, ar["Davis, Miles"])
+
+	ar.Add("Tyler, The Creator", "performer")
+	assert.Contains(t, ar, "Tyler, The Creator")
+	ai.Add("Tyler, The Creator", DiscogsArtistID, "1234")
+	assert.Contains(t, ai, "Tyler, The Creator")
+}
+func TestActorRolesCompare(t *testing.T) {
+	ar := ActorRoles{"Miles Davis": {"performer"}}
+	assert.Equal(t, 0., ar.Compare(ActorRoles{}))
+	assert.Equal(t, 1., ar.Compare(ActorRoles{"Davis, Miles": {"performer"}}))
+	assert.Less(t, ar.Compare(ActorRoles{"John Coltrane": {"performer"}}), .7)
 }
 
 func TestActorRolesFilter(t *testing.T) {
@@ -83,3 +103,22 @@ func TestActorIDsUnmarshal(t *testing.T) {
 	require.NoError(t, json.Unmarshal(jsonData, &m))
 	assert.Contains(t, m, MusicbrainzArtistID)
 }
+
+func TestActorRolesNormalize(t *testing.T) {
+	ar := ActorRoles{"John Coltrane": {"Tenor Saxophone", "instrument:tenor saxophone", "Sax"}}
+	ar.Normalize()
+	assert.Equal(t, []ActorRole{"tenor saxophone", "saxophone"}, ar["John Coltrane"])
+	assert.True(t, ar.HasRole("John Coltrane", "Tenor Sax"))
+	assert.False(t, ar.HasRole("John Coltrane", "piano"))
+}
+
+func TestActorIDsAddNormalized(t *testing.T) {
+	a := ActorsIDs{}
+	a.Add("Miles Davis", MusicbrainzArtistID, "12345")
+	a.Add("Davis, Miles", DiscogsArtistID, "23755")
+	assert.Len(t, a, 1)
+	assert.Len(t, a["Miles Davis"], 2)
+	a.Add("John Smith (3)", DiscogsArtistID, "345")
+	assert.Len(t, a, 2)
+	assert.Equal(t, "John Smith (3)", a["John Smith"][DiscogsArtistName])
+}
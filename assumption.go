@@ -2,17 +2,19 @@ package metadata
 
 // Assumption хранит результат считывания метаданных из файловых треков.
 type Assumption struct {
-	Release  *Release          `json:"release"`
-	Pictures []*PictureInAudio `json:"pictures,omitempty"`
-	Actors   ActorsIDs         `json:"actors,omitempty"`
+	Release    *Release          `json:"release"`
+	Pictures   []*PictureInAudio `json:"pictures,omitempty"`
+	Actors     ActorsIDs         `json:"actors,omitempty"`
+	ActorsInfo ActorsInfo        `json:"actors_info,omitempty"`
 }
 
 // NewAssumption создает объект типа Assumption и возвращает ссылку на него.
 // Если входной объект равен nil, поле Release будет инициализироваться.
 func NewAssumption(release *Release) *Assumption {
 	assumption := Assumption{
-		Actors:   ActorsIDs{},
-		Pictures: []*PictureInAudio{},
+		Actors:     ActorsIDs{},
+		ActorsInfo: ActorsInfo{},
+		Pictures:   []*PictureInAudio{},
 	}
 	if release == nil {
 		assumption.Release = NewRelease()
@@ -22,8 +24,10 @@ func NewAssumption(release *Release) *Assumption {
 	return &assumption
 }
 
-// Optimize оптимизирует исходный релиз и выносит графический материал из Release на уровень
-// выше, если этот материал содержит образ картинки.
+// Optimize оптимизирует исходный релиз и выносит графический материал релиза и оригинального
+// релиза на уровень выше, если этот материал содержит образ картинки. Повторяющиеся
+// по содержимому изображения сохраняются однократно, а в релизах остаются ссылки на них
+// по хешу (как в SuggestionSet.Optimize).
 func (as *Assumption) Optimize() {
 	if as.Release == nil {
 		return
@@ -36,13 +40,16 @@ func (as *Assumption) Optimize() {
 		as.Actors = as.Release.Actors
 		as.Release.Actors = nil
 	}
-	for i := len(as.Release.Pictures) - 1; i >= 0; i-- {
-		if len(as.Release.Pictures[i].Data) > 0 {
-			as.Pictures = append(as.Pictures, as.Release.Pictures[i])
-			as.Release.Pictures = as.Release.Pictures[:i]
+	if as.Release.ActorsInfo != nil {
+		if as.ActorsInfo == nil {
+			as.ActorsInfo = ActorsInfo{}
 		}
+		as.ActorsInfo.Merge(as.Release.ActorsInfo)
+		as.Release.ActorsInfo = nil
 	}
-	if len(as.Release.Pictures) == 0 {
-		as.Release.Pictures = nil
+	seen := pictureKeys(as.Pictures)
+	as.Release.Pictures = hoistPictures(&as.Pictures, as.Release.Pictures, seen)
+	if as.Release.Original != nil {
+		as.Release.Original.Pictures = hoistPictures(&as.Pictures, as.Release.Original.Pictures, seen)
 	}
 }
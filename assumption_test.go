@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,7 +27,23 @@ func TestAsumptionOptimize(t *testing.T) {
 			Data:     []byte("JPEG"),
 		})
 	assumption.Optimize()
-	assert.Empty(t, assumption.Release.Pictures)
 	require.NotEmpty(t, assumption.Pictures)
 	assert.Equal(t, PictTypeCoverFront, assumption.Pictures[0].PictType)
+	require.Len(t, assumption.Release.Pictures, 1)
+	assert.Empty(t, assumption.Release.Pictures[0].Data)
+	assert.Equal(t, assumption.Pictures[0].Hash, assumption.Release.Pictures[0].Hash)
+}
+
+func TestAssumptionOptimizeActorsInfo(t *testing.T) {
+	release := NewRelease()
+	release.Actors["Miles Davis"] = ActorIDs{ISNI: "0000000121032683"}
+	release.ActorsInfo = ActorsInfo{"Miles Davis": NewActor(ActorTypePerson)}
+	assumption := NewAssumption(release)
+	assumption.Optimize()
+	assert.Nil(t, assumption.Release.ActorsInfo)
+	require.Contains(t, assumption.ActorsInfo, "Miles Davis")
+	assert.Equal(t, ActorTypePerson, assumption.ActorsInfo["Miles Davis"].Type)
+	data, err := json.Marshal(assumption)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"actors":{"Miles Davis":{"isni":"0000000121032683"}}`)
 }
@@ -0,0 +1,167 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BarcodeType тип штрих-кода издания.
+type BarcodeType int8
+
+// Допустимые значения типа штрих-кода.
+const (
+	BarcodeUPCA BarcodeType = iota + 1
+	BarcodeEAN13
+	BarcodeEAN8
+	BarcodeJAN
+)
+
+// StrToBarcodeType ..
+var StrToBarcodeType = map[string]BarcodeType{
+	"upc-a":  BarcodeUPCA,
+	"ean-13": BarcodeEAN13,
+	"ean-8":  BarcodeEAN8,
+	"jan":    BarcodeJAN,
+}
+
+func (bt BarcodeType) String() string {
+	switch bt {
+	case BarcodeUPCA:
+		return "upc-a"
+	case BarcodeEAN13:
+		return "ean-13"
+	case BarcodeEAN8:
+		return "ean-8"
+	case BarcodeJAN:
+		return "jan"
+	}
+	return ""
+}
+
+// MarshalJSON ..
+func (bt BarcodeType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bt.String())
+}
+
+// UnmarshalJSON ..
+func (bt *BarcodeType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*bt = StrToBarcodeType[s]
+	return nil
+}
+
+// Barcode описывает проверенный штрих-код издания: UPC-A (12 цифр), EAN-13 (13 цифр),
+// JAN (EAN-13 с японским префиксом 45 или 49) или EAN-8 (8 цифр).
+type Barcode struct {
+	Type   BarcodeType
+	Digits string
+}
+
+// ParseBarcode удаляет из штрих-кода пробелы и дефисы, определяет его тип и проверяет
+// контрольную цифру.
+func ParseBarcode(s string) (*Barcode, error) {
+	digits := barcodeDigits(s)
+	if digits == "" {
+		return nil, fmt.Errorf("barcode %q: empty", s)
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return nil, fmt.Errorf("barcode %q: wrong character %q", s, r)
+		}
+	}
+	ret := &Barcode{Digits: digits}
+	switch len(digits) {
+	case 12:
+		ret.Type = BarcodeUPCA
+	case 13:
+		ret.Type = BarcodeEAN13
+		if strings.HasPrefix(digits, "45") || strings.HasPrefix(digits, "49") {
+			ret.Type = BarcodeJAN
+		}
+	case 8:
+		ret.Type = BarcodeEAN8
+	default:
+		return nil, fmt.Errorf("barcode %q: wrong length %d", s, len(digits))
+	}
+	if gs1CheckDigit(digits[:len(digits)-1]) != digits[len(digits)-1] {
+		return nil, fmt.Errorf("barcode %q: wrong check digit", s)
+	}
+	return ret, nil
+}
+
+// NormalizeBarcode возвращает проверенный штрих-код в виде строки цифр.
+func NormalizeBarcode(s string) (string, error) {
+	bc, err := ParseBarcode(s)
+	if err != nil {
+		return "", err
+	}
+	return bc.Digits, nil
+}
+
+func barcodeDigits(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", "\u00a0", "").Replace(strings.TrimSpace(s))
+}
+
+// gs1CheckDigit вычисляет контрольную цифру GS1 (UPC/EAN) для цифр без контрольной.
+func gs1CheckDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func (bc *Barcode) String() string {
+	return bc.Digits
+}
+
+// EAN13 возвращает штрих-код в форме EAN-13. Для UPC-A добавляется ведущий ноль,
+// для EAN-8 возвращается пустая строка.
+func (bc *Barcode) EAN13() string {
+	switch bc.Type {
+	case BarcodeUPCA:
+		return "0" + bc.Digits
+	case BarcodeEAN13, BarcodeJAN:
+		return bc.Digits
+	}
+	return ""
+}
+
+// UPC возвращает штрих-код в форме UPC-A. Для EAN-13 с ведущим нулем он отбрасывается,
+// для остальных штрих-кодов возвращается пустая строка.
+func (bc *Barcode) UPC() string {
+	switch {
+	case bc.Type == BarcodeUPCA:
+		return bc.Digits
+	case bc.Type == BarcodeEAN13 && bc.Digits[0] == '0':
+		return bc.Digits[1:]
+	}
+	return ""
+}
+
+// Equal проверяет совпадение штрих-кодов с учетом эквивалентности UPC-A и EAN-13.
+func (bc *Barcode) Equal(other *Barcode) bool {
+	if ean := bc.EAN13(); ean != "" {
+		return ean == other.EAN13()
+	}
+	return bc.Digits == other.Digits
+}
+
+// SameBarcode проверяет совпадение штрих-кодов. Корректные штрих-коды сравниваются
+// с учетом эквивалентности UPC-A и EAN-13, некорректные - по цифрам без разделителей.
+func SameBarcode(s1, s2 string) bool {
+	bc1, err1 := ParseBarcode(s1)
+	bc2, err2 := ParseBarcode(s2)
+	if err1 == nil && err2 == nil {
+		return bc1.Equal(bc2)
+	}
+	return barcodeDigits(s1) == barcodeDigits(s2)
+}
@@ -0,0 +1,86 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBarcode(t *testing.T) {
+	tests := []struct {
+		s      string
+		typ    BarcodeType
+		digits string
+	}{
+		{"0 77774 64292 7", BarcodeUPCA, "077774642927"},
+		{"077774642927", BarcodeUPCA, "077774642927"},
+		{"5 099902 987620", BarcodeEAN13, "5099902987620"},
+		{"4988006 555501", BarcodeJAN, "4988006555501"},
+		{"9638-5074", BarcodeEAN8, "96385074"},
+	}
+	for _, tc := range tests {
+		bc, err := ParseBarcode(tc.s)
+		require.NoError(t, err, tc.s)
+		assert.Equal(t, tc.typ, bc.Type, tc.s)
+		assert.Equal(t, tc.digits, bc.String(), tc.s)
+	}
+	for _, s := range []string{"", "077774642924", "07777464292", "0777746429X4", "96385075"} {
+		_, err := ParseBarcode(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestBarcodeConversion(t *testing.T) {
+	upc, err := ParseBarcode("077774642927")
+	require.NoError(t, err)
+	assert.Equal(t, "0077774642927", upc.EAN13())
+	assert.Equal(t, "077774642927", upc.UPC())
+	ean, err := ParseBarcode("0077774642927")
+	require.NoError(t, err)
+	assert.Equal(t, BarcodeEAN13, ean.Type)
+	assert.Equal(t, "077774642927", ean.UPC())
+	assert.True(t, upc.Equal(ean))
+	ean8, err := ParseBarcode("96385074")
+	require.NoError(t, err)
+	assert.Empty(t, ean8.EAN13())
+	assert.Empty(t, ean8.UPC())
+	assert.False(t, upc.Equal(ean8))
+}
+
+func TestSameBarcode(t *testing.T) {
+	assert.True(t, SameBarcode("0 77774 64292 7", "0077774642927"))
+	assert.False(t, SameBarcode("077774642927", "5099902987620"))
+	assert.True(t, SameBarcode("12 345", "12345"))
+}
+
+func TestPublishingBarcode(t *testing.T) {
+	pub := NewPublishing()
+	bc, err := pub.Barcode()
+	assert.NoError(t, err)
+	assert.Nil(t, bc)
+	require.NoError(t, pub.SetBarcode("0 77774 64292 7"))
+	assert.Equal(t, "077774642927", pub.IDs[PublishingBarcode])
+	assert.Error(t, pub.SetBarcode("077774642924"))
+	pub.IDs[PublishingBarcode] = "077774642924"
+	_, err = pub.Barcode()
+	assert.Error(t, err)
+}
+
+func TestPublishingCompareBarcode(t *testing.T) {
+	pub := NewPublishing()
+	pub.AddLabel(NewLabel("EMI", "CDP 7 46442 2"))
+	require.NoError(t, pub.SetBarcode("077774644228"))
+	other := NewPublishing()
+	other.AddLabel(NewLabel("EMI", "CDP 7 46442 2"))
+	require.NoError(t, other.SetBarcode("0 077774 644228"))
+	assert.Equal(t, 1., pub.Compare(other))
+	require.NoError(t, other.SetBarcode("5099902987620"))
+	assert.Equal(t, 0., pub.Compare(other))
+	other.IDs[PublishingBarcode] = "077774642924"
+	assert.Equal(t, 1., pub.Compare(other))
+
+	empty := &Publishing{}
+	require.NoError(t, empty.SetBarcode("077774644228"))
+	assert.Equal(t, "077774644228", empty.IDs[PublishingBarcode])
+}
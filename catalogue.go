@@ -0,0 +1,216 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Канонические обозначения тематических каталогов произведений и номеров опусов.
+var catalogueNames = map[string]string{
+	"op":   "Op.",
+	"opus": "Op.",
+	"bwv":  "BWV",
+	"kv":   "K.",
+	"k":    "K.",
+	"hob":  "Hob.",
+	"d":    "D.",
+	"rv":   "RV",
+	"hwv":  "HWV",
+	"woo":  "WoO",
+	"s":    "S.",
+	"l":    "L.",
+}
+
+var catalogueNumberRe = regexp.MustCompile(`(?i)\b(opus|op|bwv|kv|k|hob|d|rv|hwv|woo|s|l)` +
+	`(?:\.\s*|\s+)([ivxlc]+[a-z]?:\s*\d+[a-z]?|\d+[a-z]?)` +
+	`(?:\s*,?\s*(?:no|nr|n°)\.?\s*(\d+[a-z]?))?`)
+
+// CatalogueNumber описывает номер произведения в тематическом каталоге ("BWV 1007",
+// "K. 331", "Hob. XVI:52", "D. 960") или номер опуса ("Op. 27 No. 2").
+type CatalogueNumber struct {
+	Catalogue string
+	Number    string
+	SubNumber string
+}
+
+// ParseCatalogueNumber разбирает и нормализует номер произведения в каталоге.
+// Строка должна содержать только номер.
+func ParseCatalogueNumber(s string) (*CatalogueNumber, error) {
+	s = strings.TrimSpace(s)
+	m := catalogueNumberRe.FindStringSubmatchIndex(s)
+	if m == nil || m[0] != 0 || m[1] != len(s) || !validCataloguePrefix(s, m) {
+		return nil, fmt.Errorf("catalogue number %q: wrong format", s)
+	}
+	return newCatalogueNumber(s, m), nil
+}
+
+// ExtractCatalogueNumbers находит в названии произведения все номера каталогов
+// ("Piano Sonata No. 14, Op. 27 No. 2").
+func ExtractCatalogueNumbers(title string) []*CatalogueNumber {
+	var ret []*CatalogueNumber
+	for _, m := range catalogueNumberRe.FindAllStringSubmatchIndex(title, -1) {
+		if !validCataloguePrefix(title, m) {
+			continue
+		}
+		ret = append(ret, newCatalogueNumber(title, m))
+	}
+	return ret
+}
+
+// validCataloguePrefix отсеивает ложные совпадения однобуквенных обозначений каталогов
+// (D., K., L., S.) в тексте: обозначение должно быть заглавной буквой, не следовать
+// за "in"/"en" (тональность "in D") и не завершаться точкой после номера ("in D. 1. Allegro").
+func validCataloguePrefix(s string, m []int) bool {
+	if m[3]-m[2] != 1 {
+		return true
+	}
+	if c := s[m[2]]; c < 'A' || c > 'Z' {
+		return false
+	}
+	if m[1] < len(s) && s[m[1]] == '.' {
+		return false
+	}
+	before := strings.Fields(strings.ToLower(s[:m[2]]))
+	return len(before) == 0 || (before[len(before)-1] != "in" && before[len(before)-1] != "en")
+}
+
+func newCatalogueNumber(s string, m []int) *CatalogueNumber {
+	ret := &CatalogueNumber{
+		Catalogue: catalogueNames[strings.ToLower(s[m[2]:m[3]])],
+		Number:    strings.ToUpper(strings.ReplaceAll(s[m[4]:m[5]], " ", "")),
+	}
+	if m[6] != -1 {
+		ret.SubNumber = strings.ToLower(s[m[6]:m[7]])
+	}
+	if strings.Contains(ret.Number, ":") {
+		// римская нумерация групп каталога Хобокена, буквенный суффикс номера в нижнем регистре
+		parts := strings.SplitN(ret.Number, ":", 2)
+		ret.Number = parts[0] + ":" + strings.ToLower(parts[1])
+	} else {
+		ret.Number = strings.ToLower(ret.Number)
+	}
+	return ret
+}
+
+func (cn *CatalogueNumber) String() string {
+	ret := cn.Catalogue + " " + cn.Number
+	if cn.SubNumber != "" {
+		ret += " No. " + cn.SubNumber
+	}
+	return ret
+}
+
+// Equal проверяет совпадение номеров в одном каталоге.
+func (cn *CatalogueNumber) Equal(other *CatalogueNumber) bool {
+	return *cn == *other
+}
+
+// MarshalJSON ..
+func (cn *CatalogueNumber) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cn.String())
+}
+
+// UnmarshalJSON ..
+func (cn *CatalogueNumber) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := ParseCatalogueNumber(s)
+	if err != nil {
+		return err
+	}
+	*cn = *v
+	return nil
+}
+
+// Tonality ..
+type Tonality int8
+
+// Допустимые значения лада тональности.
+const (
+	TonalityMajor Tonality = iota + 1
+	TonalityMinor
+)
+
+// StrToTonality ..
+var StrToTonality = map[string]Tonality{
+	"major": TonalityMajor,
+	"minor": TonalityMinor,
+}
+
+func (t Tonality) String() string {
+	switch t {
+	case TonalityMajor:
+		return "major"
+	case TonalityMinor:
+		return "minor"
+	}
+	return ""
+}
+
+// MarshalJSON ..
+func (t Tonality) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON ..
+func (t *Tonality) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*t = StrToTonality[s]
+	return nil
+}
+
+const (
+	keyAccidental = `(?:\s*-?\s*(?i:(flat|sharp|b|#|♭|♯)))?`
+	keyMode       = `\s*-?\s*(?i:(major|minor|dur|moll))\b`
+)
+
+// Тональность распознается после "in"/"en", по тонике с заглавной буквы или в немецкой
+// записи через дефис ("h-moll"). Тоника в нижнем регистре без контекста ("a minor")
+// тональностью не считается.
+var keyRes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i:\b(?:in|en)\s+)([A-Ha-h])` + keyAccidental + keyMode),
+	regexp.MustCompile(`\b([A-H])` + keyAccidental + keyMode),
+	regexp.MustCompile(`\b([a-h])` + keyAccidental + `-(?i:(dur|moll))\b`),
+}
+
+// ParseKey определяет тональность произведения по названию ("Symphony No. 5 in C minor",
+// "Sonata in B-flat major", "H-Moll"). Возвращает тонику ("C", "Bb", "F#") и лад.
+// В немецкой записи B обозначает си-бемоль, а H - си.
+func ParseKey(title string) (string, Tonality) {
+	var m []string
+	for _, re := range keyRes {
+		if m = re.FindStringSubmatch(title); m != nil {
+			break
+		}
+	}
+	if m == nil {
+		return "", 0
+	}
+	mode := strings.ToLower(m[3])
+	german := mode == "dur" || mode == "moll"
+	tonic := strings.ToUpper(m[1])
+	switch {
+	case tonic == "H":
+		tonic = "B"
+	case tonic == "B" && german && m[2] == "":
+		tonic = "Bb"
+	}
+	switch strings.ToLower(m[2]) {
+	case "flat", "b", "♭":
+		tonic += "b"
+	case "sharp", "#", "♯":
+		tonic += "#"
+	}
+	tonality := TonalityMajor
+	if mode == "minor" || mode == "moll" {
+		tonality = TonalityMinor
+	}
+	return tonic, tonality
+}
@@ -0,0 +1,95 @@
+package metadata
+
+import (
+	"strings"
+	"unicode"
+
+	stringutils "github.com/ytsiuryn/go-stringutils"
+)
+
+// Значения номера в каталоге, означающие его отсутствие.
+var catnoPlaceholders = map[string]bool{
+	"none": true, "n/a": true, "na": true, "no cat": true, "no catno": true, "no number": true,
+	"unknown": true, "-": true, "--": true, "?": true,
+}
+
+// Суффиксы наименований лейблов, не влияющие на их сравнение.
+var labelNameSuffixes = map[string]bool{
+	"records": true, "recordings": true, "record": true, "music": true, "label": true,
+	"ltd": true, "limited": true, "inc": true, "incorporated": true, "llc": true,
+	"gmbh": true, "co": true, "corp": true, "corporation": true, "company": true,
+	"sa": true, "srl": true, "bv": true, "ag": true, "plc": true,
+}
+
+// NormalizeCatno приводит номер в каталоге к верхнему регистру с одиночными пробелами.
+// Значения-заглушки ("none", "n/a" и т.п.) заменяются пустой строкой.
+func NormalizeCatno(catno string) string {
+	catno = strings.Join(strings.Fields(catno), " ")
+	if catnoPlaceholders[strings.ToLower(catno)] {
+		return ""
+	}
+	return strings.ToUpper(catno)
+}
+
+// CatnoKey возвращает ключ номера в каталоге для сравнения: нормализованный номер без
+// пробелов, дефисов, точек и косых черт ("CDP 7 46001 2" -> "CDP7460012").
+func CatnoKey(catno string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, NormalizeCatno(catno))
+}
+
+// IsNotOnLabel проверяет, является ли наименование лейбла обозначением самостоятельного
+// издания без лейбла ("Not On Label", "Not On Label (The Beatles Self-released)").
+func IsNotOnLabel(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.HasPrefix(name, "not on label") || name == "no label" ||
+		name == "self-released" || name == "[no label]"
+}
+
+// NormalizeLabelName удаляет из наименования лейбла лишние пробелы и суффикс уточнения
+// Discogs вида "(2)". Обозначения изданий без лейбла заменяются пустой строкой.
+func NormalizeLabelName(name string) string {
+	if IsNotOnLabel(name) {
+		return ""
+	}
+	name = strings.Join(strings.Fields(name), " ")
+	return discogsNameSuffix.ReplaceAllString(name, "")
+}
+
+// LabelNameKey возвращает ключ наименования лейбла для сравнения: нормализованное
+// наименование в нижнем регистре без диакритических знаков, пунктуации, ведущего
+// артикля "the" и завершающих слов вида "Records", "Ltd.", "Inc.".
+func LabelNameKey(name string) string {
+	name = diacriticsReplacer.Replace(strings.ToLower(NormalizeLabelName(name)))
+	flds := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+	if len(flds) > 1 && flds[0] == "the" {
+		flds = flds[1:]
+	}
+	for len(flds) > 1 && labelNameSuffixes[flds[len(flds)-1]] {
+		flds = flds[:len(flds)-1]
+	}
+	return strings.Join(flds, " ")
+}
+
+// SameLabel проверяет, относятся ли два наименования к одному лейблу.
+func SameLabel(name1, name2 string) bool {
+	key := LabelNameKey(name1)
+	return key != "" && key == LabelNameKey(name2)
+}
+
+func labelNameSimilarity(name1, name2 string) float64 {
+	key1, key2 := LabelNameKey(name1), LabelNameKey(name2)
+	if key1 == "" || key2 == "" {
+		return 0
+	}
+	if key1 == key2 {
+		return 1
+	}
+	return stringutils.JaroWinklerDistance(key1, key2)
+}
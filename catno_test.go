@@ -0,0 +1,67 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCatno(t *testing.T) {
+	results := map[string]string{
+		" cdp  7 46001 2 ": "CDP 7 46001 2",
+		"none":             "",
+		"N/A":              "",
+		"":                 "",
+		"SRCS-9198":        "SRCS-9198",
+	}
+	for s, expected := range results {
+		assert.Equal(t, expected, NormalizeCatno(s), s)
+	}
+}
+
+func TestCatnoKey(t *testing.T) {
+	assert.Equal(t, "CDP7460012", CatnoKey("CDP 7 46001 2"))
+	assert.Equal(t, "CDP7460012", CatnoKey("cdp7460012"))
+	assert.Equal(t, "SRCS9198", CatnoKey("SRCS-9198"))
+	assert.Equal(t, "", CatnoKey("none"))
+}
+
+func TestLabelNameKey(t *testing.T) {
+	results := map[string]string{
+		"Columbia Records":             "columbia",
+		"Blue Note Records, Inc.":      "blue note",
+		"Parlophone Ltd.":              "parlophone",
+		"EMI (2)":                      "emi",
+		"The Echo Label Ltd":           "echo",
+		"Music":                        "music",
+		"Not On Label":                 "",
+		"Not On Label (Self-released)": "",
+		"Deutsche Grammophon GmbH":     "deutsche grammophon",
+		"Crépuscule":                   "crepuscule",
+	}
+	for s, expected := range results {
+		assert.Equal(t, expected, LabelNameKey(s), s)
+	}
+	assert.True(t, SameLabel("Columbia", "Columbia Records"))
+	assert.False(t, SameLabel("Not On Label", "Not On Label"))
+	assert.True(t, IsNotOnLabel("Not On Label (The Beatles Self-released)"))
+}
+
+func TestLabelCompare(t *testing.T) {
+	lbl := NewLabel("EMI", "CDP 7 46001 2")
+	assert.Equal(t, 1., lbl.Compare(NewLabel("EMI Records Ltd.", "CDP7460012")))
+	assert.Equal(t, partialLabelMatch, lbl.Compare(NewLabel("Parlophone", "cdp-7-46001-2")))
+	assert.Equal(t, partialLabelMatch, lbl.Compare(NewLabel("EMI Records", "")))
+	assert.Equal(t, partialLabelMatch, NewLabel("EMI", "none").Compare(NewLabel("EMI", "")))
+	assert.Less(t, NewLabel("Blue Note", "1").Compare(NewLabel("Columbia", "1")), 1.)
+	res := lbl.Compare(NewLabel("EMI", "CDP 7 46002 2"))
+	assert.True(t, res > 0.5 && res < 1., res)
+	assert.Equal(t, 0., NewLabel("Not On Label", "").Compare(NewLabel("Not On Label", "")))
+}
+
+func TestLabelNormalize(t *testing.T) {
+	lbl := NewLabel(" Blue  Note (3)", "bst 84003 ")
+	lbl.Normalize()
+	assert.Equal(t, "Blue Note", lbl.Label)
+	assert.Equal(t, "BST 84003", lbl.Catno)
+}
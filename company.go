@@ -0,0 +1,317 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CompanyRole роль компании в издании релиза.
+type CompanyRole int8
+
+// Допустимые значения ролей компаний.
+const (
+	CompanyLabel CompanyRole = iota + 1
+	CompanyDistributor
+	CompanyCopyright
+	CompanyPhonographicCopyright
+	CompanyPressedBy
+	CompanyManufacturedBy
+	CompanyMarketedBy
+	CompanyLicensedFrom
+	CompanyLicensedTo
+)
+
+// StrToCompanyRole ..
+var StrToCompanyRole = map[string]CompanyRole{
+	"label":                  CompanyLabel,
+	"distributor":            CompanyDistributor,
+	"copyright":              CompanyCopyright,
+	"phonographic_copyright": CompanyPhonographicCopyright,
+	"pressed_by":             CompanyPressedBy,
+	"manufactured_by":        CompanyManufacturedBy,
+	"marketed_by":            CompanyMarketedBy,
+	"licensed_from":          CompanyLicensedFrom,
+	"licensed_to":            CompanyLicensedTo,
+}
+
+// DiscogsCompanyRoles отображает типы компаний Discogs на роли компаний.
+var DiscogsCompanyRoles = map[string]CompanyRole{
+	"Distributed By":             CompanyDistributor,
+	"Copyright (c)":              CompanyCopyright,
+	"Phonographic Copyright (p)": CompanyPhonographicCopyright,
+	"Pressed By":                 CompanyPressedBy,
+	"Manufactured By":            CompanyManufacturedBy,
+	"Made By":                    CompanyManufacturedBy,
+	"Marketed By":                CompanyMarketedBy,
+	"Licensed From":              CompanyLicensedFrom,
+	"Licensed To":                CompanyLicensedTo,
+}
+
+func (cr CompanyRole) String() string {
+	switch cr {
+	case CompanyLabel:
+		return "label"
+	case CompanyDistributor:
+		return "distributor"
+	case CompanyCopyright:
+		return "copyright"
+	case CompanyPhonographicCopyright:
+		return "phonographic_copyright"
+	case CompanyPressedBy:
+		return "pressed_by"
+	case CompanyManufacturedBy:
+		return "manufactured_by"
+	case CompanyMarketedBy:
+		return "marketed_by"
+	case CompanyLicensedFrom:
+		return "licensed_from"
+	case CompanyLicensedTo:
+		return "licensed_to"
+	}
+	return ""
+}
+
+// MarshalJSON ..
+func (cr CompanyRole) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cr.String())
+}
+
+// UnmarshalJSON ..
+func (cr *CompanyRole) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*cr = StrToCompanyRole[s]
+	return nil
+}
+
+// Company описывает компанию, участвовавшую в издании релиза.
+// Parent указывает материнскую компанию или лейбл, импринтом которого является компания.
+type Company struct {
+	Name   string      `json:"name"`
+	Role   CompanyRole `json:"role"`
+	Parent string      `json:"parent,omitempty"`
+	IDs    LabelIDs    `json:"ids,omitempty"`
+}
+
+// NewCompany создает объект Company.
+func NewCompany(name string, role CompanyRole) *Company {
+	return &Company{Name: NormalizeLabelName(name), Role: role, IDs: LabelIDs{}}
+}
+
+// NoticeType тип уведомления об авторских правах.
+type NoticeType int8
+
+// Допустимые значения типов уведомлений об авторских правах.
+const (
+	// NoticeCopyright авторское право на оформление и тексты (©).
+	NoticeCopyright NoticeType = iota + 1
+	// NoticePhonographic права изготовителя фонограммы (℗).
+	NoticePhonographic
+)
+
+// StrToNoticeType ..
+var StrToNoticeType = map[string]NoticeType{
+	"copyright":    NoticeCopyright,
+	"phonographic": NoticePhonographic,
+}
+
+func (nt NoticeType) String() string {
+	switch nt {
+	case NoticeCopyright:
+		return "copyright"
+	case NoticePhonographic:
+		return "phonographic"
+	}
+	return ""
+}
+
+// Symbol возвращает символ уведомления ("©" или "℗").
+func (nt NoticeType) Symbol() string {
+	switch nt {
+	case NoticeCopyright:
+		return "©"
+	case NoticePhonographic:
+		return "℗"
+	}
+	return ""
+}
+
+// MarshalJSON ..
+func (nt NoticeType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nt.String())
+}
+
+// UnmarshalJSON ..
+func (nt *NoticeType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*nt = StrToNoticeType[s]
+	return nil
+}
+
+// Notice описывает уведомление об авторских правах: тип, год (или период с Year по
+// EndYear) и правообладателя.
+type Notice struct {
+	Type    NoticeType `json:"type"`
+	Year    int        `json:"year,omitempty"`
+	EndYear int        `json:"end_year,omitempty"`
+	Holder  string     `json:"holder,omitempty"`
+}
+
+var (
+	noticeSymbols = regexp.MustCompile(`^(?i)(?:(phonographic\s+copyright|copyright)\b\s*)?` +
+		`(℗|©|\(p\)|\(c\)|p\b|c\b)?\s*(?:&\s*(℗|©|\(p\)|\(c\)|p\b|c\b)\s*)?`)
+	noticeYear = regexp.MustCompile(`^((?:19|20)\d\d)(?:\s*[-–]\s*((?:19|20)\d\d|\d\d))?\b[,.]?\s*`)
+)
+
+// ParseNotice разбирает строку уведомления об авторских правах вида "© 1969 Apple Corps
+// Ltd.", "(P) 1987 EMI Records Ltd", "℗ & © 2009 Apple Corps", "P & C 1990 X",
+// "© 1969-1970 Apple" или "Copyright (c) 1969 Apple". Для объединенного уведомления
+// возвращаются уведомления обоих типов.
+func ParseNotice(s string) ([]*Notice, error) {
+	s = strings.TrimSpace(s)
+	m := noticeSymbols.FindStringSubmatch(s)
+	if m == nil || m[0] == "" {
+		return nil, fmt.Errorf("notice %q: unknown symbol", s)
+	}
+	symbols := m[2:]
+	if m[2] == "" && m[3] == "" {
+		symbols = []string{"©"}
+		if strings.HasPrefix(strings.ToLower(m[1]), "phonographic") {
+			symbols[0] = "℗"
+		}
+	} else if strings.HasPrefix(strings.ToLower(m[1]), "phonographic") && m[3] == "" {
+		symbols = []string{"℗"}
+	}
+	rest := s[len(m[0]):]
+	var year, endYear int
+	if ym := noticeYear.FindStringSubmatch(rest); ym != nil {
+		year, _ = strconv.Atoi(ym[1])
+		if ym[2] != "" {
+			endYear, _ = strconv.Atoi(ym[2])
+			if endYear < 100 {
+				endYear += year / 100 * 100
+			}
+		}
+		rest = rest[len(ym[0]):]
+	}
+	holder := strings.TrimSpace(rest)
+	var ret []*Notice
+	for _, symbol := range symbols {
+		if symbol != "" {
+			ret = append(ret, &Notice{
+				Type: noticeTypeBySymbol(symbol), Year: year, EndYear: endYear, Holder: holder})
+		}
+	}
+	return ret, nil
+}
+
+func noticeTypeBySymbol(symbol string) NoticeType {
+	switch strings.ToLower(symbol) {
+	case "℗", "(p)", "p":
+		return NoticePhonographic
+	}
+	return NoticeCopyright
+}
+
+func (n *Notice) String() string {
+	parts := []string{n.Type.Symbol()}
+	switch {
+	case n.Year != 0 && n.EndYear != 0:
+		parts = append(parts, fmt.Sprintf("%d-%d", n.Year, n.EndYear))
+	case n.Year != 0:
+		parts = append(parts, strconv.Itoa(n.Year))
+	}
+	if n.Holder != "" {
+		parts = append(parts, n.Holder)
+	}
+	return strings.Join(parts, " ")
+}
+
+// AddCompany добавляет компанию с указанной ролью и возвращает ее. Если компания с тем же
+// наименованием и ролью уже добавлена, возвращается она.
+func (pub *Publishing) AddCompany(name string, role CompanyRole) *Company {
+	for _, c := range pub.Companies {
+		if c.Role == role && SameLabel(c.Name, name) {
+			return c
+		}
+	}
+	c := NewCompany(name, role)
+	pub.Companies = append(pub.Companies, c)
+	return c
+}
+
+// CompaniesByRole возвращает компании с указанной ролью. Для роли CompanyLabel в начало
+// перечня добавляются лейблы из Labels.
+func (pub *Publishing) CompaniesByRole(role CompanyRole) []*Company {
+	var ret []*Company
+	if role == CompanyLabel {
+		for _, lbl := range pub.Labels {
+			ret = append(ret, &Company{
+				Name: lbl.Label, Role: CompanyLabel, Parent: lbl.Parent, IDs: lbl.IDs})
+		}
+	}
+	for _, c := range pub.Companies {
+		if c.Role == role {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// Imprints возвращает наименования лейблов и компаний, являющихся импринтами указанной
+// компании.
+func (pub *Publishing) Imprints(parent string) []string {
+	var ret []string
+	for _, lbl := range pub.Labels {
+		if lbl.Parent != "" && SameLabel(lbl.Parent, parent) {
+			ret = append(ret, lbl.Label)
+		}
+	}
+	for _, c := range pub.Companies {
+		if c.Parent != "" && SameLabel(c.Parent, parent) {
+			ret = append(ret, c.Name)
+		}
+	}
+	return ret
+}
+
+// AddNotice разбирает и добавляет уведомление об авторских правах. Правообладатель
+// уведомления добавляется в перечень компаний с ролью CompanyCopyright или
+// CompanyPhonographicCopyright.
+func (pub *Publishing) AddNotice(s string) error {
+	notices, err := ParseNotice(s)
+	if err != nil {
+		return err
+	}
+	for _, n := range notices {
+		pub.Notices = append(pub.Notices, n)
+		if n.Holder == "" {
+			continue
+		}
+		if n.Type == NoticeCopyright {
+			pub.AddCompany(n.Holder, CompanyCopyright)
+		} else {
+			pub.AddCompany(n.Holder, CompanyPhonographicCopyright)
+		}
+	}
+	return nil
+}
+
+// NoticesByType возвращает уведомления об авторских правах указанного типа.
+func (pub *Publishing) NoticesByType(typ NoticeType) []*Notice {
+	var ret []*Notice
+	for _, n := range pub.Notices {
+		if n.Type == typ {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
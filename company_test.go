@@ -0,0 +1,107 @@
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNotice(t *testing.T) {
+	notices, err := ParseNotice("© 1969 Apple Corps Ltd.")
+	require.NoError(t, err)
+	assert.Equal(t, []*Notice{{Type: NoticeCopyright, Year: 1969, Holder: "Apple Corps Ltd."}}, notices)
+
+	notices, err = ParseNotice("(P) 1987 EMI Records Ltd")
+	require.NoError(t, err)
+	assert.Equal(t, []*Notice{{Type: NoticePhonographic, Year: 1987, Holder: "EMI Records Ltd"}}, notices)
+
+	notices, err = ParseNotice("℗ & © 2009 Apple Corps")
+	require.NoError(t, err)
+	require.Len(t, notices, 2)
+	assert.Equal(t, NoticePhonographic, notices[0].Type)
+	assert.Equal(t, NoticeCopyright, notices[1].Type)
+	assert.Equal(t, "© 2009 Apple Corps", notices[1].String())
+
+	notices, err = ParseNotice("(c) Sony Music")
+	require.NoError(t, err)
+	assert.Equal(t, "© Sony Music", notices[0].String())
+
+	notices, err = ParseNotice("© 1969-1970 Apple")
+	require.NoError(t, err)
+	assert.Equal(t, []*Notice{{Type: NoticeCopyright, Year: 1969, EndYear: 1970, Holder: "Apple"}}, notices)
+	assert.Equal(t, "© 1969-1970 Apple", notices[0].String())
+	notices, err = ParseNotice("© 1969–70 Apple")
+	require.NoError(t, err)
+	assert.Equal(t, 1970, notices[0].EndYear)
+
+	notices, err = ParseNotice("P & C 1990 X")
+	require.NoError(t, err)
+	assert.Equal(t, []*Notice{
+		{Type: NoticePhonographic, Year: 1990, Holder: "X"},
+		{Type: NoticeCopyright, Year: 1990, Holder: "X"}}, notices)
+
+	notices, err = ParseNotice("Copyright (c) 1969 Apple")
+	require.NoError(t, err)
+	assert.Equal(t, []*Notice{{Type: NoticeCopyright, Year: 1969, Holder: "Apple"}}, notices)
+	notices, err = ParseNotice("Copyright 1969 Apple")
+	require.NoError(t, err)
+	assert.Equal(t, []*Notice{{Type: NoticeCopyright, Year: 1969, Holder: "Apple"}}, notices)
+	notices, err = ParseNotice("Phonographic Copyright (p) 1987 EMI")
+	require.NoError(t, err)
+	assert.Equal(t, []*Notice{{Type: NoticePhonographic, Year: 1987, Holder: "EMI"}}, notices)
+	notices, err = ParseNotice("Phonographic Copyright 1987 EMI")
+	require.NoError(t, err)
+	assert.Equal(t, []*Notice{{Type: NoticePhonographic, Year: 1987, Holder: "EMI"}}, notices)
+
+	_, err = ParseNotice("Apple Corps")
+	assert.Error(t, err)
+	_, err = ParseNotice("Capitol Records")
+	assert.Error(t, err)
+}
+
+func TestPublishingCompanies(t *testing.T) {
+	pub := NewPublishing()
+	lbl := NewLabel("Blue Note", "BST 84003")
+	lbl.Parent = "Liberty Records"
+	pub.AddLabel(lbl)
+	dist := pub.AddCompany("EMI Records Ltd.", CompanyDistributor)
+	assert.Equal(t, dist, pub.AddCompany("EMI Records", CompanyDistributor))
+	pressing := pub.AddCompany("Capitol Records Pressing Plant, Jacksonville", CompanyPressedBy)
+	pressing.Parent = "Capitol Records"
+	require.NoError(t, pub.AddNotice("℗ & © 1969 Liberty Records Inc."))
+
+	assert.Len(t, pub.CompaniesByRole(CompanyDistributor), 1)
+	labels := pub.CompaniesByRole(CompanyLabel)
+	require.Len(t, labels, 1)
+	assert.Equal(t, "Blue Note", labels[0].Name)
+	assert.Len(t, pub.CompaniesByRole(CompanyCopyright), 1)
+	assert.Len(t, pub.CompaniesByRole(CompanyPhonographicCopyright), 1)
+	assert.Len(t, pub.NoticesByType(NoticePhonographic), 1)
+	assert.Equal(t, []string{"Blue Note"}, pub.Imprints("Liberty"))
+	assert.Equal(t, []string{"Capitol Records Pressing Plant, Jacksonville"},
+		pub.Imprints("Capitol Records"))
+	assert.Equal(t, CompanyPressedBy, DiscogsCompanyRoles["Pressed By"])
+}
+
+func TestPublishingJSON(t *testing.T) {
+	var pub Publishing
+	require.NoError(t, json.Unmarshal([]byte(
+		`{"labels":[{"label":"Columbia","catno":"CL 1355"}],"ids":{"barcode":"077774642927"}}`),
+		&pub))
+	require.Len(t, pub.Labels, 1)
+	assert.Equal(t, "CL 1355", pub.Labels[0].Catno)
+	assert.Equal(t, "077774642927", pub.IDs[PublishingBarcode])
+
+	pub.AddCompany("CBS", CompanyDistributor)
+	require.NoError(t, pub.AddNotice("© 1959 Columbia Records"))
+	data, err := json.Marshal(&pub)
+	require.NoError(t, err)
+	var pub2 Publishing
+	require.NoError(t, json.Unmarshal(data, &pub2))
+	assert.Equal(t, pub.Companies[0].Name, pub2.Companies[0].Name)
+	assert.Equal(t, pub.Companies[0].Role, pub2.Companies[0].Role)
+	assert.Equal(t, pub.Notices, pub2.Notices)
+	assert.False(t, pub2.IsEmpty())
+}
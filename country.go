@@ -0,0 +1,113 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Коды псевдо-регионов MusicBrainz.
+const (
+	CountryEurope    = "XE"
+	CountryWorldwide = "XW"
+	CountryUnknown   = "XU"
+)
+
+// countryCodes содержит коды стран ISO 3166-1 alpha-2, исторические коды (SU, YU, CS, DD)
+// и коды псевдо-регионов MusicBrainz.
+var countryCodes = map[string]bool{}
+
+func init() {
+	for _, code := range strings.Fields(`
+		AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN
+		BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ
+		DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL
+		GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM
+		JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME
+		MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP
+		NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD
+		SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+		TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW
+		SU YU CS DD XC XG XE XW XU`) {
+		countryCodes[code] = true
+	}
+}
+
+// countryNames отображает распространенные наименования стран и регионов (в т.ч.
+// наименования Discogs) на коды.
+var countryNames = map[string]string{
+	"europe":         CountryEurope,
+	"worldwide":      CountryWorldwide,
+	"unknown":        CountryUnknown,
+	"uk":             "GB",
+	"united kingdom": "GB",
+	"england":        "GB",
+	"us":             "US",
+	"usa":            "US",
+	"united states":  "US",
+	"canada":         "CA",
+	"germany":        "DE",
+	"west germany":   "DE",
+	"east germany":   "DD",
+	"france":         "FR",
+	"italy":          "IT",
+	"spain":          "ES",
+	"netherlands":    "NL",
+	"holland":        "NL",
+	"belgium":        "BE",
+	"sweden":         "SE",
+	"norway":         "NO",
+	"denmark":        "DK",
+	"finland":        "FI",
+	"austria":        "AT",
+	"switzerland":    "CH",
+	"poland":         "PL",
+	"czech republic": "CZ",
+	"czechoslovakia": "XC",
+	"hungary":        "HU",
+	"greece":         "GR",
+	"portugal":       "PT",
+	"ireland":        "IE",
+	"russia":         "RU",
+	"ussr":           "SU",
+	"yugoslavia":     "YU",
+	"ukraine":        "UA",
+	"japan":          "JP",
+	"china":          "CN",
+	"taiwan":         "TW",
+	"south korea":    "KR",
+	"australia":      "AU",
+	"new zealand":    "NZ",
+	"brazil":         "BR",
+	"argentina":      "AR",
+	"mexico":         "MX",
+	"south africa":   "ZA",
+	"india":          "IN",
+	"israel":         "IL",
+	"turkey":         "TR",
+}
+
+// NormalizeCountry приводит код или наименование страны либо псевдо-региона ("Europe",
+// "Worldwide") к коду ISO 3166-1 alpha-2 или коду псевдо-региона MusicBrainz.
+// Сводные регионы Discogs вида "UK & Europe" приводятся к CountryEurope.
+func NormalizeCountry(country string) (string, error) {
+	country = strings.TrimSpace(country)
+	if country == "" {
+		return "", nil
+	}
+	if upper := strings.ToUpper(country); countryCodes[upper] {
+		return upper, nil
+	}
+	lower := strings.ToLower(strings.Join(strings.Fields(country), " "))
+	if code, ok := countryNames[lower]; ok {
+		return code, nil
+	}
+	if strings.Contains(lower, "europe") {
+		return CountryEurope, nil
+	}
+	return "", fmt.Errorf("country %q: unknown code", country)
+}
+
+// IsRegion проверяет, является ли код псевдо-регионом, а не страной.
+func IsRegion(code string) bool {
+	return code == CountryEurope || code == CountryWorldwide || code == CountryUnknown
+}
@@ -0,0 +1,196 @@
+package metadata
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Разметка примечаний Discogs: https://support.discogs.com/hc/en-us/articles/360005055373
+var (
+	discogsArtistMarkup   = regexp.MustCompile(`\[a=([^\]]+)\]`)
+	discogsArtistIDMarkup = regexp.MustCompile(`\[a(\d+)\]`)
+	discogsOtherMarkup    = regexp.MustCompile(`\[[lmrg]=([^\]]+)\]`)
+	discogsNameSuffix     = regexp.MustCompile(`\s*\(\d+\)$`)
+	discogsTracksSuffix   = regexp.MustCompile(`\s*\(tracks?:\s*([^)]*)\)\s*$`)
+)
+
+// Credit описывает отдельное упоминание актора в титрах релиза Discogs.
+// Tracks содержит перечень позиций и диапазонов треков в виде "A1 to B2".
+// Если Tracks пуст, упоминание относится к релизу целиком.
+type Credit struct {
+	Name        ActorName
+	DiscogsName string
+	DiscogsID   string
+	Role        ActorRole
+	Tracks      []string
+}
+
+// ParseCredit разбирает строку титров Discogs вида "Bass, Vocals – Ron Carter (2), Nemo
+// (tracks: A1 to B2)" и возвращает по одному объекту Credit на каждую пару актор-роль.
+func ParseCredit(s string) []*Credit {
+	s = strings.TrimSpace(s)
+	var tracks []string
+	if m := discogsTracksSuffix.FindStringSubmatch(s); m != nil {
+		tracks = splitOutsideBrackets(m[1])
+		s = s[:len(s)-len(m[0])]
+	}
+	var rolesPart, namesPart string
+	for _, sep := range []string{" – ", " — ", " - "} {
+		if i := strings.Index(s, sep); i != -1 {
+			rolesPart, namesPart = s[:i], s[i+len(sep):]
+			break
+		}
+	}
+	if namesPart == "" {
+		return nil
+	}
+	var ret []*Credit
+	for _, name := range splitOutsideBrackets(namesPart) {
+		for _, role := range splitOutsideBrackets(rolesPart) {
+			credit := parseCreditName(name)
+			if credit.Name == "" {
+				continue
+			}
+			credit.Role = role
+			credit.Tracks = tracks
+			ret = append(ret, credit)
+		}
+	}
+	return ret
+}
+
+// NewCredit создает объект Credit из отдельных полей, как они приходят в API Discogs
+// (поля name, role и tracks объекта extraartists).
+func NewCredit(name, role, tracks string) *Credit {
+	credit := parseCreditName(name)
+	credit.Role = strings.TrimSpace(role)
+	if tracks = strings.TrimSpace(tracks); tracks != "" {
+		credit.Tracks = splitOutsideBrackets(tracks)
+	}
+	return credit
+}
+
+func parseCreditName(name string) *Credit {
+	credit := &Credit{}
+	name = strings.TrimSpace(name)
+	if m := discogsArtistIDMarkup.FindStringSubmatch(name); m != nil {
+		credit.DiscogsID = m[1]
+		name = strings.TrimSpace(discogsArtistIDMarkup.ReplaceAllString(name, ""))
+	}
+	credit.Name, credit.DiscogsName = SplitDiscogsName(StripDiscogsMarkup(name))
+	return credit
+}
+
+// StripDiscogsMarkup удаляет из текста разметку ссылок Discogs ("[a=Artist]", "[l=Label]"
+// и т.д.), оставляя только наименования.
+func StripDiscogsMarkup(s string) string {
+	s = discogsArtistMarkup.ReplaceAllString(s, "$1")
+	return discogsOtherMarkup.ReplaceAllString(s, "$1")
+}
+
+// MarkupActors возвращает перечень акторов, упомянутых в тексте с помощью разметки
+// Discogs "[a=Artist]".
+func MarkupActors(s string) []ActorName {
+	var ret []ActorName
+	for _, m := range discogsArtistMarkup.FindAllStringSubmatch(s, -1) {
+		ret = append(ret, discogsNameSuffix.ReplaceAllString(strings.TrimSpace(m[1]), ""))
+	}
+	return ret
+}
+
+// ApplyCredits распределяет роли акторов по релизу и его трекам.
+// Упоминания с указанием треков добавляются в ActorRoles соответствующих треков,
+// остальные - в ActorRoles релиза. Роли приводятся к каноническому виду, а идентификаторы
+// Discogs сохраняются в Actors релиза.
+func (r *Release) ApplyCredits(credits []*Credit) {
+	for _, credit := range credits {
+		if credit.DiscogsID != "" {
+			r.Actors.Add(credit.Name, DiscogsArtistID, credit.DiscogsID)
+		}
+		if credit.DiscogsName != "" {
+			r.Actors.Add(credit.Name, DiscogsArtistName, credit.DiscogsName)
+		}
+		role := CanonicalRole(credit.Role)
+		if role == "" {
+			continue
+		}
+		if len(credit.Tracks) == 0 {
+			r.ActorRoles.Add(credit.Name, role)
+			continue
+		}
+		for _, track := range r.TracksInRanges(credit.Tracks) {
+			if track.ActorRoles == nil {
+				track.ActorRoles = ActorRoles{}
+			}
+			track.ActorRoles.Add(credit.Name, role)
+		}
+	}
+}
+
+// TracksInRanges возвращает треки релиза, попадающие в перечень позиций и диапазонов
+// вида "A1 to B2". Диапазон включает крайние треки и все треки между ними в порядке
+// следования в релизе.
+func (r *Release) TracksInRanges(ranges []string) []*Track {
+	var ret []*Track
+	included := map[*Track]bool{}
+	for _, rng := range ranges {
+		bounds := strings.SplitN(rng, " to ", 2)
+		first := r.trackIndexByPosition(bounds[0])
+		last := first
+		if len(bounds) == 2 {
+			last = r.trackIndexByPosition(bounds[1])
+		}
+		if first == -1 || last == -1 {
+			continue
+		}
+		if first > last {
+			first, last = last, first
+		}
+		for _, track := range r.Tracks[first : last+1] {
+			if !included[track] {
+				included[track] = true
+				ret = append(ret, track)
+			}
+		}
+	}
+	return ret
+}
+
+func (r *Release) trackIndexByPosition(pos string) int {
+	for i, track := range r.Tracks {
+		if SamePosition(track.Position, pos) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SamePosition сравнивает две позиции трека с учетом номера диска и нормализации номера
+// трека ("1-5" и "1.05", "A1" и "a1").
+func SamePosition(pos1, pos2 string) bool {
+	return ParsePosition(strings.ToUpper(pos1)).Equal(ParsePosition(strings.ToUpper(pos2)))
+}
+
+func splitOutsideBrackets(s string) []string {
+	var ret []string
+	var depth, start int
+	for i, r := range s {
+		switch r {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				if fld := strings.TrimSpace(s[start:i]); fld != "" {
+					ret = append(ret, fld)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if fld := strings.TrimSpace(s[start:]); fld != "" {
+		ret = append(ret, fld)
+	}
+	return ret
+}
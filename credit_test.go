@@ -0,0 +1,74 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCredit(t *testing.T) {
+	credits := ParseCredit("Bass – Ron Carter (tracks: A1 to B2)")
+	require.Len(t, credits, 1)
+	assert.Equal(t, "Ron Carter", credits[0].Name)
+	assert.Equal(t, "Bass", credits[0].Role)
+	assert.Equal(t, []string{"A1 to B2"}, credits[0].Tracks)
+
+	credits = ParseCredit("Guitar [Acoustic, 12-string], Vocals – John Smith (3), Nemo*")
+	require.Len(t, credits, 4)
+	assert.Equal(t, "John Smith", credits[0].Name)
+	assert.Equal(t, "John Smith (3)", credits[0].DiscogsName)
+	assert.Equal(t, "Guitar [Acoustic, 12-string]", credits[0].Role)
+	assert.Equal(t, "Nemo", credits[3].Name)
+	assert.Empty(t, credits[3].DiscogsName)
+
+	credits = ParseCredit("Producer – [a=Teo Macero]")
+	require.Len(t, credits, 1)
+	assert.Equal(t, "Teo Macero", credits[0].Name)
+
+	assert.Empty(t, ParseCredit("Ron Carter"))
+}
+
+func TestNewCredit(t *testing.T) {
+	credit := NewCredit("Ron Carter (2)", "Bass", "A1 to A3, B2")
+	assert.Equal(t, "Ron Carter", credit.Name)
+	assert.Equal(t, "Ron Carter (2)", credit.DiscogsName)
+	assert.Equal(t, []string{"A1 to A3", "B2"}, credit.Tracks)
+}
+
+func TestDiscogsMarkup(t *testing.T) {
+	notes := "Recorded by [a=Rudy Van Gelder] for [l=Blue Note], mastered by [a=Nemo (2)]."
+	assert.Equal(t, "Recorded by Rudy Van Gelder for Blue Note, mastered by Nemo (2).",
+		StripDiscogsMarkup(notes))
+	assert.Equal(t, []ActorName{"Rudy Van Gelder", "Nemo"}, MarkupActors(notes))
+}
+
+func TestSamePosition(t *testing.T) {
+	assert.True(t, SamePosition("A1", "a1"))
+	assert.True(t, SamePosition("1-5", "1.05"))
+	assert.True(t, SamePosition("1", "01"))
+	assert.False(t, SamePosition("1-5", "2-5"))
+	assert.False(t, SamePosition("", ""))
+}
+
+func TestReleaseApplyCredits(t *testing.T) {
+	r := NewRelease()
+	for _, pos := range []string{"A1", "A2", "B1", "B2", "C1"} {
+		track := NewTrack()
+		track.Position = pos
+		r.Tracks = append(r.Tracks, track)
+	}
+	credits := ParseCredit("Bass – Ron Carter (2) (tracks: A2 to B2)")
+	credits = append(credits, ParseCredit("Producer – Teo Macero")...)
+	credits = append(credits, NewCredit("[a12345]Nemo", "Drums", "C1"))
+	r.ApplyCredits(credits)
+
+	assert.Empty(t, r.Tracks[0].ActorRoles)
+	for _, track := range r.Tracks[1:4] {
+		assert.Equal(t, []ActorRole{"bass"}, track.ActorRoles["Ron Carter"])
+	}
+	assert.Equal(t, []ActorRole{"drums"}, r.Tracks[4].ActorRoles["Nemo"])
+	assert.Equal(t, []ActorRole{RoleProducer}, r.ActorRoles["Teo Macero"])
+	assert.Equal(t, "Ron Carter (2)", r.Actors["Ron Carter"][DiscogsArtistName])
+	assert.Equal(t, "12345", r.Actors["Nemo"][DiscogsArtistID])
+}
@@ -0,0 +1,225 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PartialDate описывает дату с возможно неизвестными месяцем и днем.
+// Нулевые значения полей означают отсутствие сведений.
+type PartialDate struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// ParsePartialDate разбирает дату в виде "YYYY", "YYYY-MM" или "YYYY-MM-DD".
+// Нулевые месяц и день ("1969-00-00") считаются неизвестными.
+func ParsePartialDate(s string) (PartialDate, error) {
+	var ret PartialDate
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ret, nil
+	}
+	flds := strings.Split(s, "-")
+	if len(flds) > 3 || len(flds[0]) != 4 {
+		return ret, fmt.Errorf("partial date %q: wrong format", s)
+	}
+	vals := make([]int, len(flds))
+	for i, fld := range flds {
+		v, err := strconv.Atoi(fld)
+		if err != nil || i > 0 && len(fld) != 2 {
+			return ret, fmt.Errorf("partial date %q: wrong format", s)
+		}
+		vals[i] = v
+	}
+	ret.Year = vals[0]
+	if len(vals) > 1 {
+		ret.Month = vals[1]
+	}
+	if len(vals) > 2 {
+		ret.Day = vals[2]
+	}
+	if ret.Month == 0 {
+		ret.Day = 0
+	}
+	if err := ret.Validate(); err != nil {
+		return PartialDate{}, err
+	}
+	return ret, nil
+}
+
+// Validate проверяет корректность заполненных полей даты.
+func (pd PartialDate) Validate() error {
+	switch {
+	case pd.Year < 0 || pd.Year > 9999:
+		return fmt.Errorf("partial date %q: wrong year", pd)
+	case pd.Month < 0 || pd.Month > 12:
+		return fmt.Errorf("partial date %q: wrong month", pd)
+	case pd.Day != 0 && pd.Month == 0:
+		return fmt.Errorf("partial date %q: day without month", pd)
+	case pd.Day != 0:
+		t := time.Date(pd.Year, time.Month(pd.Month), pd.Day, 0, 0, 0, 0, time.UTC)
+		if t.Day() != pd.Day {
+			return fmt.Errorf("partial date %q: wrong day", pd)
+		}
+	}
+	return nil
+}
+
+// IsEmpty проверяет отсутствие сведений о дате.
+func (pd PartialDate) IsEmpty() bool {
+	return pd == PartialDate{}
+}
+
+func (pd PartialDate) String() string {
+	switch {
+	case pd.Year == 0:
+		return ""
+	case pd.Month == 0:
+		return fmt.Sprintf("%04d", pd.Year)
+	case pd.Day == 0:
+		return fmt.Sprintf("%04d-%02d", pd.Year, pd.Month)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", pd.Year, pd.Month, pd.Day)
+}
+
+// Compare сравнивает даты и возвращает -1, 0 или 1.
+// Менее точная дата предшествует более точной с теми же известными полями.
+func (pd PartialDate) Compare(other PartialDate) int {
+	for _, k := range [][2]int{
+		{pd.Year, other.Year}, {pd.Month, other.Month}, {pd.Day, other.Day}} {
+		switch {
+		case k[0] < k[1]:
+			return -1
+		case k[0] > k[1]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Before проверяет, предшествует ли дата другой дате.
+func (pd PartialDate) Before(other PartialDate) bool {
+	return pd.Compare(other) < 0
+}
+
+// Contains проверяет, попадает ли более точная дата в период, описываемый датой
+// ("1969" содержит "1969-09-26").
+func (pd PartialDate) Contains(other PartialDate) bool {
+	return !pd.IsEmpty() && pd.Year == other.Year &&
+		(pd.Month == 0 || pd.Month == other.Month) &&
+		(pd.Day == 0 || pd.Day == other.Day)
+}
+
+// MarshalJSON ..
+func (pd PartialDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pd.String())
+}
+
+// UnmarshalJSON ..
+func (pd *PartialDate) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := ParsePartialDate(s)
+	if err != nil {
+		return err
+	}
+	*pd = v
+	return nil
+}
+
+// DateRange описывает период между двумя частичными датами включительно.
+// Незаполненная дата окончания означает однодневный или открытый период.
+type DateRange struct {
+	Begin PartialDate
+	End   PartialDate
+}
+
+// NewDateRange создает период и проверяет корректность его границ.
+func NewDateRange(begin, end PartialDate) (*DateRange, error) {
+	ret := &DateRange{Begin: begin, End: end}
+	if err := ret.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ParseDateRange разбирает период в формате ISO 8601 "1969-09-26/1969-09-27" или
+// одиночную дату.
+func ParseDateRange(s string) (*DateRange, error) {
+	flds := strings.SplitN(s, "/", 2)
+	begin, err := ParsePartialDate(flds[0])
+	if err != nil {
+		return nil, err
+	}
+	var end PartialDate
+	if len(flds) == 2 {
+		if end, err = ParsePartialDate(flds[1]); err != nil {
+			return nil, err
+		}
+	}
+	return NewDateRange(begin, end)
+}
+
+// Validate проверяет корректность границ периода.
+func (dr *DateRange) Validate() error {
+	if err := dr.Begin.Validate(); err != nil {
+		return err
+	}
+	if err := dr.End.Validate(); err != nil {
+		return err
+	}
+	if !dr.End.IsEmpty() && dr.End.Before(dr.Begin) {
+		return fmt.Errorf("date range %q: end before begin", dr)
+	}
+	return nil
+}
+
+// IsEmpty проверяет отсутствие сведений о периоде.
+func (dr *DateRange) IsEmpty() bool {
+	return dr == nil || dr.Begin.IsEmpty() && dr.End.IsEmpty()
+}
+
+// Contains проверяет, попадает ли дата в период.
+func (dr *DateRange) Contains(date PartialDate) bool {
+	if dr.IsEmpty() || date.IsEmpty() {
+		return false
+	}
+	if dr.End.IsEmpty() {
+		return dr.Begin.Contains(date)
+	}
+	return (dr.Begin.Compare(date) <= 0 || dr.Begin.Contains(date)) &&
+		(date.Compare(dr.End) <= 0 || dr.End.Contains(date))
+}
+
+func (dr *DateRange) String() string {
+	if dr.End.IsEmpty() || dr.End == dr.Begin {
+		return dr.Begin.String()
+	}
+	return dr.Begin.String() + "/" + dr.End.String()
+}
+
+// MarshalJSON ..
+func (dr *DateRange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dr.String())
+}
+
+// UnmarshalJSON ..
+func (dr *DateRange) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := ParseDateRange(s)
+	if err != nil {
+		return err
+	}
+	*dr = *v
+	return nil
+}
@@ -0,0 +1,59 @@
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePartialDate(t *testing.T) {
+	results := map[string]PartialDate{
+		"":           {},
+		"1969":       {Year: 1969},
+		"1969-09":    {Year: 1969, Month: 9},
+		"1969-09-26": {Year: 1969, Month: 9, Day: 26},
+		"1969-00-00": {Year: 1969},
+	}
+	for s, expected := range results {
+		pd, err := ParsePartialDate(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, pd, s)
+	}
+	for _, s := range []string{"69", "1969-9", "1969-13", "1969-02-30", "1969-09-26-01", "abcd"} {
+		_, err := ParsePartialDate(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestPartialDateString(t *testing.T) {
+	assert.Equal(t, "", PartialDate{}.String())
+	assert.Equal(t, "1969", PartialDate{Year: 1969}.String())
+	assert.Equal(t, "1969-09", PartialDate{Year: 1969, Month: 9}.String())
+	assert.Equal(t, "1969-09-26", PartialDate{Year: 1969, Month: 9, Day: 26}.String())
+}
+
+func TestPartialDateCompare(t *testing.T) {
+	year := PartialDate{Year: 1969}
+	month := PartialDate{Year: 1969, Month: 9}
+	day := PartialDate{Year: 1969, Month: 9, Day: 26}
+	assert.True(t, year.Before(month))
+	assert.True(t, month.Before(day))
+	assert.True(t, day.Before(PartialDate{Year: 1970}))
+	assert.Equal(t, 0, day.Compare(day))
+	assert.True(t, year.Contains(day))
+	assert.True(t, month.Contains(day))
+	assert.False(t, month.Contains(PartialDate{Year: 1969, Month: 10}))
+	assert.False(t, PartialDate{}.Contains(day))
+}
+
+func TestPartialDateJSON(t *testing.T) {
+	data, err := json.Marshal(PartialDate{Year: 1969, Month: 9})
+	require.NoError(t, err)
+	assert.Equal(t, `"1969-09"`, string(data))
+	var pd PartialDate
+	require.NoError(t, json.Unmarshal(data, &pd))
+	assert.Equal(t, PartialDate{Year: 1969, Month: 9}, pd)
+	assert.Error(t, json.Unmarshal([]byte(`"1969-13"`), &pd))
+}
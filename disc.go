@@ -3,6 +3,8 @@ package metadata
 import (
 	"encoding/json"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 
 	collection "github.com/ytsiuryn/go-collection"
@@ -18,6 +20,11 @@ const (
 	MediaDigital
 	MediaReeL
 	MediaLP
+	MediaDVDA
+	MediaBluRay
+	MediaCassette
+	MediaShellac
+	MediaMiniDisc
 )
 
 // MediaID тип для перечисления идентификаторов дисков во внешних БД.
@@ -38,7 +45,18 @@ func (mid MediaID) String() string {
 	case DiscID:
 		return "disc_id"
 	}
-	return ""
+	return registeredIDKey(EntityMedia, uint8(mid))
+}
+
+// MarshalText позволяет использовать MediaID в качестве ключа словаря JSON.
+func (mid MediaID) MarshalText() ([]byte, error) {
+	return []byte(mid.String()), nil
+}
+
+// UnmarshalText получает MediaID из ключа словаря JSON (см. parseIDKey).
+func (mid *MediaID) UnmarshalText(b []byte) error {
+	*mid = MediaID(parseIDKey(EntityMedia, string(b)))
+	return nil
 }
 
 // MediaIDs представляет словарь идентификаторов медиа-дисков релиза во внешних БД.
@@ -61,32 +79,93 @@ func (mids *MediaIDs) UnmarshalJSON(b []byte) error {
 	}
 	*mids = make(MediaIDs, len(x))
 	for k, v := range x {
-		(*mids)[StrToMediaID[k]] = v
+		(*mids)[MediaID(LookupID(EntityMedia, k))] = v
 	}
 	return nil
 }
 
 // StrToMedia ..
 var StrToMedia = map[string]Media{
-	"sacd":    MediaSACD,
-	"cd":      MediaCD,
-	"digital": MediaDigital,
-	"reel":    MediaReeL,
-	"lp":      MediaLP,
+	"sacd":     MediaSACD,
+	"cd":       MediaCD,
+	"digital":  MediaDigital,
+	"reel":     MediaReeL,
+	"lp":       MediaLP,
+	"dvda":     MediaDVDA,
+	"bluray":   MediaBluRay,
+	"cassette": MediaCassette,
+	"shellac":  MediaShellac,
+	"minidisc": MediaMiniDisc,
+}
+
+// ChannelMode описывает режим каналов звука на носителе.
+type ChannelMode int8
+
+// Допустимые значения режима каналов.
+const (
+	ChannelModeMono ChannelMode = iota + 1
+	ChannelModeStereo
+	ChannelModeQuadraphonic
+	ChannelModeMultichannel
+)
+
+// StrToChannelMode ..
+var StrToChannelMode = map[string]ChannelMode{
+	"mono":         ChannelModeMono,
+	"stereo":       ChannelModeStereo,
+	"quadraphonic": ChannelModeQuadraphonic,
+	"multichannel": ChannelModeMultichannel,
+}
+
+// DiscLayer описывает слои многослойного носителя (SACD, DVD, Blu-ray).
+type DiscLayer int8
+
+// Допустимые значения слоев носителя.
+const (
+	DiscLayerSingle DiscLayer = iota + 1
+	DiscLayerDual
+	DiscLayerHybrid
+)
+
+// StrToDiscLayer ..
+var StrToDiscLayer = map[string]DiscLayer{
+	"single": DiscLayerSingle,
+	"dual":   DiscLayerDual,
+	"hybrid": DiscLayerHybrid,
 }
 
-// DiscFormat ..
+// DiscFormat описывает носитель и его физические свойства.
+// Size указывается в дюймах, Weight - в граммах, Speed - в оборотах в минуту
+// ("33 1/3", "45", "78").
+// Свойства, не имеющие отдельного поля, хранятся в Attrs.
 type DiscFormat struct {
-	Media `json:"media,omitempty"`
-	Attrs []string `json:"attrs,omitempty"`
+	Media       Media       `json:"media,omitempty"`
+	Speed       string      `json:"speed,omitempty"`
+	Size        int         `json:"size,omitempty"`
+	Weight      int         `json:"weight,omitempty"`
+	Colour      string      `json:"colour,omitempty"`
+	Layer       DiscLayer   `json:"layer,omitempty"`
+	ChannelMode ChannelMode `json:"channel_mode,omitempty"`
+	Attrs       []string    `json:"attrs,omitempty"`
 }
 
+var (
+	discSpeed  = regexp.MustCompile(`(?i)^(33\s*(?:1/3|⅓)?|45|78|16)\s*rpm$`)
+	discSize   = regexp.MustCompile(`(?i)^(\d{1,2})\s*(?:"|''|”|-?inch)$`)
+	discWeight = regexp.MustCompile(`(?i)^(\d{2,3})\s*(?:g|gr|gram|grams)$`)
+	discColour = regexp.MustCompile(`(?i)\b(black|white|red|blue|green|yellow|orange|purple|` +
+		`pink|gold|silver|grey|gray|brown|clear|transparent|translucent|marbled|splatter|` +
+		`coloured|colored)\b`)
+	discLabel = regexp.MustCompile(`(?i)\blabel\b`)
+)
+
 // Disc описывает дополнительные свойства диска. Сам номер диска указывается в объекте трека.
 type Disc struct {
-	Number int                `json:"number"`
-	Title  string             `json:"title,omitempty"`
-	Format *DiscFormat        `json:"format,omitempty"`
-	IDs    map[MediaID]string `json:"ids,omitempty"`
+	Number      int                `json:"number"`
+	Title       string             `json:"title,omitempty"`
+	TotalTracks int                `json:"total_tracks,omitempty"`
+	Format      *DiscFormat        `json:"format,omitempty"`
+	IDs         map[MediaID]string `json:"ids,omitempty"`
 }
 
 // NewDisc creates and initialize a new DiscExtra object.
@@ -97,23 +176,140 @@ func NewDisc(num int) *Disc {
 // DecodeMedia converts a string representation of media to a const of Media type.
 func DecodeMedia(v string) Media {
 	var ret Media
-	upperVal := strings.ToUpper(v)
+	upperVal := strings.ToUpper(strings.TrimSpace(v))
 	switch {
-	case collection.ContainsStr(upperVal, []string{"LP", "VINYL"}):
+	case collection.ContainsStr(upperVal, []string{"LP", "VINYL"}), isVinylSize(upperVal):
 		ret = MediaLP
+	case collection.ContainsStr(upperVal, []string{"SHELLAC", "78 RPM", "78RPM"}):
+		ret = MediaShellac
 	case strings.Index(upperVal, "SACD") != -1:
 		ret = MediaSACD
+	case collection.ContainsStr(upperVal, []string{"MINIDISC", "MD"}):
+		ret = MediaMiniDisc
 	case strings.Index(upperVal, "CD") != -1:
 		ret = MediaCD
 	case collection.ContainsStr(upperVal, []string{
 		"[TR24][OF]", "[TR24][SM][OF]", "[DSD][OF]", "[DXD][OF]", "[DVDA][OF]"}):
 		ret = MediaDigital
+	case collection.ContainsStr(upperVal, []string{"DVDA", "DVD-A", "DVD-AUDIO", "DVD AUDIO"}):
+		ret = MediaDVDA
+	case collection.ContainsStr(upperVal, []string{
+		"BLU-RAY", "BLURAY", "BLU-RAY AUDIO", "BD", "BD-A", "PURE AUDIO BLU-RAY"}):
+		ret = MediaBluRay
+	case collection.ContainsStr(upperVal, []string{"CASSETTE", "CASS", "MC", "TAPE"}):
+		ret = MediaCassette
 	case strings.Index(upperVal, "REEL") != -1:
 		ret = MediaReeL
 	}
 	return ret
 }
 
+func isVinylSize(v string) bool {
+	m := discSize.FindStringSubmatch(v)
+	return m != nil && collection.ContainsStr(m[1], []string{"7", "10", "12"})
+}
+
+// DecodeAttr разбирает свойство носителя (скорость, размер, вес, цвет, слои, режим каналов)
+// и заполняет соответствующее поле. Возвращает false, если свойство не распознано.
+func (df *DiscFormat) DecodeAttr(attr string) bool {
+	attr = strings.TrimSpace(attr)
+	lowerAttr := strings.ToLower(attr)
+	if m := discSpeed.FindStringSubmatch(attr); m != nil {
+		df.Speed = strings.Join(strings.Fields(strings.Replace(m[1], "⅓", " 1/3", 1)), " ")
+		if df.Speed == "33" {
+			df.Speed = "33 1/3"
+		}
+		return true
+	}
+	if m := discSize.FindStringSubmatch(attr); m != nil {
+		df.Size, _ = strconv.Atoi(m[1])
+		return true
+	}
+	if m := discWeight.FindStringSubmatch(attr); m != nil {
+		df.Weight, _ = strconv.Atoi(m[1])
+		return true
+	}
+	switch lowerAttr {
+	case "mono":
+		df.ChannelMode = ChannelModeMono
+		return true
+	case "stereo":
+		df.ChannelMode = ChannelModeStereo
+		return true
+	case "quadraphonic", "quad":
+		df.ChannelMode = ChannelModeQuadraphonic
+		return true
+	case "multichannel", "5.1", "surround":
+		df.ChannelMode = ChannelModeMultichannel
+		return true
+	case "hybrid":
+		df.Layer = DiscLayerHybrid
+		return true
+	case "single layer", "single-layer", "sl":
+		df.Layer = DiscLayerSingle
+		return true
+	case "dual layer", "dual-layer", "dl":
+		df.Layer = DiscLayerDual
+		return true
+	}
+	if discColour.MatchString(attr) && !discLabel.MatchString(attr) {
+		df.Colour = attr
+		return true
+	}
+	return false
+}
+
+// DecodeDiscFormat формирует объект DiscFormat по перечню свойств носителя в свободной
+// форме (например, ["Vinyl", "LP", "12\"", "33 ⅓ RPM", "180g", "Red"]).
+// Нераспознанные свойства сохраняются в Attrs.
+func DecodeDiscFormat(props []string) *DiscFormat {
+	df := &DiscFormat{}
+	for _, prop := range props {
+		if prop = strings.TrimSpace(prop); prop == "" {
+			continue
+		}
+		media := DecodeMedia(prop)
+		// размер носителя указывает на винил, только если тип носителя еще не известен
+		if media != 0 && (df.Media == 0 || !isVinylSize(strings.ToUpper(prop))) {
+			df.Media = media
+		}
+		if !df.DecodeAttr(prop) && media == 0 && !collection.ContainsStr(prop, df.Attrs) {
+			df.Attrs = append(df.Attrs, prop)
+		}
+	}
+	return df
+}
+
+// Merge дополняет объект незаполненными свойствами другого объекта.
+func (df *DiscFormat) Merge(other *DiscFormat) {
+	if df.Media == 0 {
+		df.Media = other.Media
+	}
+	if df.Speed == "" {
+		df.Speed = other.Speed
+	}
+	if df.Size == 0 {
+		df.Size = other.Size
+	}
+	if df.Weight == 0 {
+		df.Weight = other.Weight
+	}
+	if df.Colour == "" {
+		df.Colour = other.Colour
+	}
+	if df.Layer == 0 {
+		df.Layer = other.Layer
+	}
+	if df.ChannelMode == 0 {
+		df.ChannelMode = other.ChannelMode
+	}
+	for _, attr := range other.Attrs {
+		if !collection.ContainsStr(attr, df.Attrs) {
+			df.Attrs = append(df.Attrs, attr)
+		}
+	}
+}
+
 func (m Media) String() string {
 	switch m {
 	case MediaSACD:
@@ -126,6 +322,16 @@ func (m Media) String() string {
 		return "reel"
 	case MediaLP:
 		return "lp"
+	case MediaDVDA:
+		return "dvda"
+	case MediaBluRay:
+		return "bluray"
+	case MediaCassette:
+		return "cassette"
+	case MediaShellac:
+		return "shellac"
+	case MediaMiniDisc:
+		return "minidisc"
 	}
 	return ""
 }
@@ -142,6 +348,62 @@ func (m *Media) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (cm ChannelMode) String() string {
+	switch cm {
+	case ChannelModeMono:
+		return "mono"
+	case ChannelModeStereo:
+		return "stereo"
+	case ChannelModeQuadraphonic:
+		return "quadraphonic"
+	case ChannelModeMultichannel:
+		return "multichannel"
+	}
+	return ""
+}
+
+// MarshalJSON ..
+func (cm ChannelMode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cm.String())
+}
+
+// UnmarshalJSON ..
+func (cm *ChannelMode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*cm = StrToChannelMode[s]
+	return nil
+}
+
+func (dl DiscLayer) String() string {
+	switch dl {
+	case DiscLayerSingle:
+		return "single"
+	case DiscLayerDual:
+		return "dual"
+	case DiscLayerHybrid:
+		return "hybrid"
+	}
+	return ""
+}
+
+// MarshalJSON ..
+func (dl DiscLayer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dl.String())
+}
+
+// UnmarshalJSON ..
+func (dl *DiscLayer) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*dl = StrToDiscLayer[s]
+	return nil
+}
+
 // Compare a DiscFormat object with other one.
 func (df *DiscFormat) Compare(other *DiscFormat) float64 {
 	if df != nil && other != nil && df.Media == other.Media {
@@ -150,6 +412,22 @@ func (df *DiscFormat) Compare(other *DiscFormat) float64 {
 	return 0.
 }
 
+// UnmarshalJSON получает объект DiscFormat из значения JSON. Поддерживается также
+// прежний формат, где носитель указывался строкой ("cd").
+func (df *DiscFormat) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		*df = DiscFormat{}
+		return json.Unmarshal(b, &df.Media)
+	}
+	type discFormat DiscFormat
+	var x discFormat
+	if err := json.Unmarshal(b, &x); err != nil {
+		return err
+	}
+	*df = DiscFormat(x)
+	return nil
+}
+
 // IsEmpty проверяет объект на пустоту.
 func (df *DiscFormat) IsEmpty() bool {
 	return df == nil || reflect.DeepEqual(DiscFormat{}, *df)
@@ -20,11 +20,17 @@ func TestDecodeMedia(t *testing.T) {
 		"[DXD][OF]":      MediaDigital,
 		"[DVDA][OF]":     MediaDigital,
 		"REEL":           MediaReeL,
+		"12\"":           MediaLP,
+		"Shellac":        MediaShellac,
+		"DVD-Audio":      MediaDVDA,
+		"Blu-ray":        MediaBluRay,
+		"Cassette":       MediaCassette,
+		"MiniDisc":       MediaMiniDisc,
+		"Hybrid":         0,
+		"5\"":            0,
 	}
 	for k, v := range results {
-		if DecodeMedia(k) != v {
-			t.Fail()
-		}
+		assert.Equal(t, v, DecodeMedia(k), k)
 	}
 }
 
@@ -73,3 +79,71 @@ func TestDiscClean(t *testing.T) {
 	d.Clean()
 	assert.True(t, d.Format.IsEmpty())
 }
+
+func TestDecodeDiscFormat(t *testing.T) {
+	df := DecodeDiscFormat([]string{"Vinyl", "12\"", "33 ⅓ RPM", "180 g", "Red Marbled", "Gatefold"})
+	assert.Equal(t, MediaLP, df.Media)
+	assert.Equal(t, 12, df.Size)
+	assert.Equal(t, "33 1/3", df.Speed)
+	assert.Equal(t, 180, df.Weight)
+	assert.Equal(t, "Red Marbled", df.Colour)
+	assert.Equal(t, []string{"Gatefold"}, df.Attrs)
+
+	df = DecodeDiscFormat([]string{"Shellac", "10\"", "78 RPM", "Mono"})
+	assert.Equal(t, MediaShellac, df.Media)
+	assert.Equal(t, 10, df.Size)
+	assert.Equal(t, "78", df.Speed)
+	assert.Equal(t, ChannelModeMono, df.ChannelMode)
+
+	df = DecodeDiscFormat([]string{"SACD", "Hybrid", "Multichannel"})
+	assert.Equal(t, MediaSACD, df.Media)
+	assert.Equal(t, DiscLayerHybrid, df.Layer)
+	assert.Equal(t, ChannelModeMultichannel, df.ChannelMode)
+	assert.Empty(t, df.Attrs)
+
+	df = DecodeDiscFormat([]string{"Shellac", "10\""})
+	assert.Equal(t, MediaShellac, df.Media)
+	assert.Equal(t, 10, df.Size)
+	df = DecodeDiscFormat([]string{"Reel-To-Reel", "7\""})
+	assert.Equal(t, MediaReeL, df.Media)
+	assert.Equal(t, 7, df.Size)
+	df = DecodeDiscFormat([]string{"7\"", "45 RPM"})
+	assert.Equal(t, MediaLP, df.Media)
+
+	df = DecodeDiscFormat([]string{"Vinyl", "White Label", "Promo"})
+	assert.Empty(t, df.Colour)
+	assert.Equal(t, []string{"White Label", "Promo"}, df.Attrs)
+}
+
+func TestDiscFormatMerge(t *testing.T) {
+	df := &DiscFormat{Media: MediaShellac}
+	df.Merge(&DiscFormat{Media: MediaLP, Size: 10})
+	assert.Equal(t, MediaShellac, df.Media)
+	assert.Equal(t, 10, df.Size)
+	df = &DiscFormat{}
+	df.Merge(&DiscFormat{Media: MediaLP})
+	assert.Equal(t, MediaLP, df.Media)
+}
+
+func TestDiscFormatUnmarshalLegacy(t *testing.T) {
+	var d Disc
+	require.NoError(t, json.Unmarshal([]byte(`{"number":1,"format":"cd"}`), &d))
+	require.NotNil(t, d.Format)
+	assert.Equal(t, MediaCD, d.Format.Media)
+	require.NoError(t, json.Unmarshal([]byte(`{"number":1,"format":{"media":"lp","size":12}}`), &d))
+	assert.Equal(t, MediaLP, d.Format.Media)
+	assert.Equal(t, 12, d.Format.Size)
+}
+
+func TestDiscFormatMarshalAndUnmarshal(t *testing.T) {
+	df := &DiscFormat{
+		Media: MediaLP, Speed: "45", Size: 7, Weight: 40, Colour: "Clear",
+		Layer: DiscLayerSingle, ChannelMode: ChannelModeStereo, Attrs: []string{"Jukebox"}}
+	data, err := json.Marshal(df)
+	require.NoError(t, err)
+	assert.Equal(t, `{"media":"lp","speed":"45","size":7,"weight":40,"colour":"Clear",`+
+		`"layer":"single","channel_mode":"stereo","attrs":["Jukebox"]}`, string(data))
+	other := &DiscFormat{}
+	require.NoError(t, json.Unmarshal(data, other))
+	assert.Equal(t, df, other)
+}
@@ -0,0 +1,161 @@
+package metadata
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	collection "github.com/ytsiuryn/go-collection"
+)
+
+var (
+	featuredInBrackets = regexp.MustCompile(
+		`(?i)\s*[(\[]\s*(?:feat\.?|ft\.?|featuring)\s+([^)\]]+)[)\]]`)
+	featuredAtEnd = regexp.MustCompile(
+		`(?i)\s+(?:feat\.|ft\.|featuring)\s+(.+?)(\s*[(\[].*|\s+-\s+.*)?$`)
+	featuredDelim = regexp.MustCompile(`(?i)\s*[,&]\s*|\s+(?:and|x|\+|/)\s+`)
+)
+
+// ExtractFeatured отделяет от названия трека фрагмент с приглашенными исполнителями вида
+// "(feat. X & Y)", "[ft. X]" или "feat. X" в конце названия (перед сведениями о версии
+// в скобках или после тире).
+// Возвращает название без фрагмента и перечень приглашенных исполнителей в порядке
+// упоминания. Имена из перечня known не разбиваются (см. SplitFeatured).
+func ExtractFeatured(title string, known ...ActorName) (string, []ActorName) {
+	var names []ActorName
+	for _, m := range featuredInBrackets.FindAllStringSubmatch(title, -1) {
+		names = append(names, SplitFeatured(m[1], known...)...)
+	}
+	title = featuredInBrackets.ReplaceAllString(title, "")
+	if m := featuredAtEnd.FindStringSubmatch(title); m != nil {
+		names = append(names, SplitFeatured(m[1], known...)...)
+		title = featuredAtEnd.ReplaceAllString(title, "$2")
+	}
+	return strings.TrimSpace(title), names
+}
+
+// SplitFeatured разбивает перечень приглашенных исполнителей по общепринятым
+// разделителям (",", "&", "and", "+", "/", "x").
+// Фрагменты, образующие вместе имя из перечня known (например, отдельно указанных
+// акторов трека), не разделяются. Фрагмент вида "& the Y" также
+// считается продолжением предыдущего имени ("Kool & the Gang").
+func SplitFeatured(s string, known ...ActorName) []ActorName {
+	var parts, delims []string
+	last := 0
+	for _, loc := range featuredDelim.FindAllStringIndex(s, -1) {
+		parts = append(parts, s[last:loc[0]])
+		delims = append(delims, s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	parts = append(parts, s[last:])
+	var ret []ActorName
+	for i := 0; i < len(parts); {
+		j := knownRun(parts, delims, i, known)
+		name := parts[i]
+		for k := i + 1; k <= j; k++ {
+			name += delims[k-1] + parts[k]
+		}
+		for j+1 < len(parts) && strings.HasPrefix(strings.ToLower(strings.TrimSpace(parts[j+1])), "the ") {
+			name += delims[j] + parts[j+1]
+			j++
+		}
+		if name = strings.TrimSpace(name); name != "" {
+			ret = append(ret, name)
+		}
+		i = j + 1
+	}
+	return ret
+}
+
+// knownRun возвращает индекс последнего фрагмента наиболее длинной последовательности,
+// начинающейся с фрагмента i и образующей известное имя, или i.
+func knownRun(parts, delims []string, i int, known []ActorName) int {
+	ret := i
+	name := parts[i]
+	for k := i + 1; k < len(parts); k++ {
+		name += delims[k-1] + parts[k]
+		for _, knownName := range known {
+			if SameActor(name, knownName) {
+				ret = k
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// FeaturedTitle формирует название трека с приглашенными исполнителями для тегов
+// проигрывателей, ожидающих их упоминания в названии: "Title (feat. X, Y & Z)".
+func FeaturedTitle(title string, names []ActorName) string {
+	switch len(names) {
+	case 0:
+		return title
+	case 1:
+		return title + " (feat. " + names[0] + ")"
+	}
+	last := len(names) - 1
+	return title + " (feat. " + strings.Join(names[:last], ", ") + " & " + names[last] + ")"
+}
+
+// ExtractFeatured переносит приглашенных исполнителей из названия трека в ActorRoles
+// с ролью RoleFeatured и возвращает их перечень. Порядок упоминания сохраняется в
+// Featuring. Уже известные акторы трека не разделяются на отдельные имена.
+func (track *Track) ExtractFeatured() []ActorName {
+	var known []ActorName
+	for name := range track.Actors {
+		known = append(known, name)
+	}
+	for name := range track.ActorRoles {
+		known = append(known, name)
+	}
+	title, names := ExtractFeatured(track.Title, known...)
+	if len(names) == 0 {
+		return nil
+	}
+	track.Title = title
+	if track.ActorRoles == nil {
+		track.ActorRoles = ActorRoles{}
+	}
+	for i, name := range names {
+		name = track.ActorRoles.Name(name)
+		names[i] = name
+		track.ActorRoles.Add(name, RoleFeatured)
+		if !collection.ContainsStr(name, track.Featuring) {
+			track.Featuring = append(track.Featuring, name)
+		}
+	}
+	return names
+}
+
+func (track *Track) isFeatured(name ActorName) bool {
+	for _, role := range track.ActorRoles[name] {
+		if CanonicalRole(role) == RoleFeatured {
+			return true
+		}
+	}
+	return false
+}
+
+// Featured возвращает перечень приглашенных исполнителей трека в порядке упоминания.
+// Исполнители, порядок упоминания которых неизвестен, следуют за ними в алфавитном
+// порядке.
+func (track *Track) Featured() []ActorName {
+	var ret, rest []ActorName
+	for _, name := range track.Featuring {
+		if track.isFeatured(name) {
+			ret = append(ret, name)
+		}
+	}
+	for name := range track.ActorRoles {
+		if track.isFeatured(name) && !collection.ContainsStr(name, ret) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(ret, rest...)
+}
+
+// FeaturedTitle возвращает название трека с упоминанием приглашенных исполнителей.
+func (track *Track) FeaturedTitle() string {
+	return FeaturedTitle(track.Title, track.Featured())
+}
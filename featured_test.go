@@ -0,0 +1,65 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractFeatured(t *testing.T) {
+	title, names := ExtractFeatured("Song Name (feat. X & Y)")
+	assert.Equal(t, "Song Name", title)
+	assert.Equal(t, []ActorName{"X", "Y"}, names)
+	title, names = ExtractFeatured("Song [ft. AC/DC]")
+	assert.Equal(t, "Song", title)
+	assert.Equal(t, []ActorName{"AC/DC"}, names)
+	title, names = ExtractFeatured("Song (Radio Edit) featuring A, B and C")
+	assert.Equal(t, "Song (Radio Edit)", title)
+	assert.Equal(t, []ActorName{"A", "B", "C"}, names)
+	title, names = ExtractFeatured("Song (feat. Earth, Wind & Fire)", "Earth, Wind & Fire")
+	assert.Equal(t, "Song", title)
+	assert.Equal(t, []ActorName{"Earth, Wind & Fire"}, names)
+	title, names = ExtractFeatured("Song feat. X (Remix)")
+	assert.Equal(t, "Song (Remix)", title)
+	assert.Equal(t, []ActorName{"X"}, names)
+	title, names = ExtractFeatured("Song feat. X & Y [Live] - Radio Edit")
+	assert.Equal(t, "Song [Live] - Radio Edit", title)
+	assert.Equal(t, []ActorName{"X", "Y"}, names)
+	title, names = ExtractFeatured("Song ft. X - Radio Edit")
+	assert.Equal(t, "Song - Radio Edit", title)
+	assert.Equal(t, []ActorName{"X"}, names)
+	_, names = ExtractFeatured("Song (feat. Kool & the Gang, Zed)")
+	assert.Equal(t, []ActorName{"Kool & the Gang", "Zed"}, names)
+	_, names = ExtractFeatured("Song (feat. Amy, Nice & Easy)", "Nice & Easy")
+	assert.Equal(t, []ActorName{"Amy", "Nice & Easy"}, names)
+	title, names = ExtractFeatured("Song (With Love)")
+	assert.Equal(t, "Song (With Love)", title)
+	assert.Empty(t, names)
+}
+
+func TestFeaturedTitle(t *testing.T) {
+	assert.Equal(t, "Song", FeaturedTitle("Song", nil))
+	assert.Equal(t, "Song (feat. X)", FeaturedTitle("Song", []ActorName{"X"}))
+	assert.Equal(t, "Song (feat. X, Y & Z)", FeaturedTitle("Song", []ActorName{"X", "Y", "Z"}))
+}
+
+func TestTrackExtractFeatured(t *testing.T) {
+	track := NewTrack()
+	track.Title = "Song Name (feat. X & Y)"
+	assert.Equal(t, []ActorName{"X", "Y"}, track.ExtractFeatured())
+	assert.Equal(t, "Song Name", track.Title)
+	assert.Equal(t, []ActorRole{RoleFeatured}, track.ActorRoles["X"])
+	assert.Equal(t, "Song Name (feat. X & Y)", track.FeaturedTitle())
+	assert.Empty(t, track.ExtractFeatured())
+
+	track = NewTrack()
+	track.Title = "Song (feat. Zed & Amy)"
+	assert.Equal(t, []ActorName{"Zed", "Amy"}, track.ExtractFeatured())
+	assert.Equal(t, "Song (feat. Zed & Amy)", track.FeaturedTitle())
+
+	track = NewTrack()
+	track.Title = "Song (feat. Zed)"
+	track.ActorRoles = ActorRoles{"Amy": {RoleFeatured}}
+	track.ExtractFeatured()
+	assert.Equal(t, []ActorName{"Zed", "Amy"}, track.Featured())
+}
@@ -8,4 +8,5 @@ require (
 	github.com/ytsiuryn/go-intutils v0.0.2
 	github.com/ytsiuryn/go-stringutils v0.0.4
 	github.com/ytsiuryn/go-world v0.0.2
+	golang.org/x/image v0.18.0
 )
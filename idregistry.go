@@ -0,0 +1,489 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EntityKind тип сущности, к которой относится идентификатор во внешней БД.
+type EntityKind uint8
+
+// Допустимые значения типа сущности.
+const (
+	EntityRelease EntityKind = iota + 1
+	EntityMaster
+	EntityArtist
+	EntityLabel
+	EntityRecording
+	EntityTrack
+	EntityWork
+	EntityMedia
+	EntityPublishing
+)
+
+// StrToEntityKind ..
+var StrToEntityKind = map[string]EntityKind{
+	"release":    EntityRelease,
+	"master":     EntityMaster,
+	"artist":     EntityArtist,
+	"label":      EntityLabel,
+	"recording":  EntityRecording,
+	"track":      EntityTrack,
+	"work":       EntityWork,
+	"media":      EntityMedia,
+	"publishing": EntityPublishing,
+}
+
+func (ek EntityKind) String() string {
+	switch ek {
+	case EntityRelease:
+		return "release"
+	case EntityMaster:
+		return "master"
+	case EntityArtist:
+		return "artist"
+	case EntityLabel:
+		return "label"
+	case EntityRecording:
+		return "recording"
+	case EntityTrack:
+		return "track"
+	case EntityWork:
+		return "work"
+	case EntityMedia:
+		return "media"
+	case EntityPublishing:
+		return "publishing"
+	}
+	return ""
+}
+
+// MarshalJSON ..
+func (ek EntityKind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ek.String())
+}
+
+// UnmarshalJSON ..
+func (ek *EntityKind) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*ek = StrToEntityKind[s]
+	return nil
+}
+
+// Namespace описывает пространство имен идентификаторов внешнего сервиса.
+// Key используется как ключ словарей идентификаторов в JSON. Validate, URL и ParseURL
+// необязательны.
+type Namespace struct {
+	Key      string
+	Entities []EntityKind
+	// Validate проверяет значение идентификатора.
+	Validate func(id string) error
+	// URL формирует ссылку на страницу сущности с идентификатором во внешнем сервисе.
+	URL func(kind EntityKind, id string) string
+	// ParseURL извлекает из ссылки тип сущности и идентификатор. Если ссылка не относится
+	// к пространству имен, возвращается false.
+	ParseURL func(u *url.URL) (EntityKind, string, bool)
+}
+
+// Supports проверяет применимость пространства имен к типу сущности.
+func (ns *Namespace) Supports(kind EntityKind) bool {
+	for _, ek := range ns.Entities {
+		if ek == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Значения перечислений идентификаторов, назначаемые зарегистрированным пространствам
+// имен, начинаются с firstRegisteredID, чтобы не пересекаться со встроенными значениями.
+const firstRegisteredID = 128
+
+var (
+	// registryMu защищает реестр пространств имен и назначенные при регистрации значения
+	// перечислений идентификаторов. Словари StrTo*ID содержат только встроенные значения
+	// и при регистрации не изменяются.
+	registryMu sync.RWMutex
+	namespaces = map[string]*Namespace{}
+	// registeredIDs и registeredKeys хранят назначенные значения перечислений и их ключи
+	// по типам сущностей.
+	registeredIDs  = map[EntityKind]map[uint8]string{}
+	registeredKeys = map[EntityKind]map[string]uint8{}
+)
+
+// RegisterNamespace регистрирует пространство имен идентификаторов. Для типов сущностей,
+// у которых еще нет идентификатора с ключом Key, назначается новое значение
+// соответствующего перечисления (ReleaseID, ActorID, LabelID и т.д.), так что ключ
+// становится допустимым в словарях идентификаторов и в JSON. Назначенное значение
+// возвращает LookupID.
+func RegisterNamespace(ns *Namespace) error {
+	if ns.Key == "" || len(ns.Entities) == 0 {
+		return fmt.Errorf("namespace %q: key and entities are required", ns.Key)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := namespaces[ns.Key]; ok {
+		return fmt.Errorf("namespace %q: already registered", ns.Key)
+	}
+	for _, kind := range ns.Entities {
+		if kind.String() == "" {
+			return fmt.Errorf("namespace %q: unknown entity kind %d", ns.Key, kind)
+		}
+	}
+	for _, kind := range ns.Entities {
+		if err := registerEntityID(idSpace(kind), ns.Key); err != nil {
+			return err
+		}
+	}
+	namespaces[ns.Key] = ns
+	return nil
+}
+
+// MustRegisterNamespace регистрирует пространство имен и паникует при ошибке.
+func MustRegisterNamespace(ns *Namespace) {
+	if err := RegisterNamespace(ns); err != nil {
+		panic(err)
+	}
+}
+
+// LookupNamespace возвращает зарегистрированное пространство имен по ключу.
+func LookupNamespace(key string) (*Namespace, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	ns, ok := namespaces[key]
+	return ns, ok
+}
+
+// Namespaces возвращает упорядоченный по ключу перечень пространств имен, применимых
+// к типу сущности.
+func Namespaces(kind EntityKind) []*Namespace {
+	var ret []*Namespace
+	for _, ns := range allNamespaces() {
+		if ns.Supports(kind) {
+			ret = append(ret, ns)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Key < ret[j].Key })
+	return ret
+}
+
+// allNamespaces возвращает упорядоченный по ключу перечень всех пространств имен.
+func allNamespaces() []*Namespace {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	ret := make([]*Namespace, 0, len(namespaces))
+	for _, ns := range namespaces {
+		ret = append(ret, ns)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Key < ret[j].Key })
+	return ret
+}
+
+// ValidateID проверяет значение идентификатора в зарегистрированном пространстве имен.
+func ValidateID(key, id string) error {
+	ns, ok := LookupNamespace(key)
+	if !ok {
+		return fmt.Errorf("namespace %q: not registered", key)
+	}
+	if ns.Validate == nil {
+		return nil
+	}
+	if err := ns.Validate(id); err != nil {
+		return fmt.Errorf("%s %q: %w", key, id, err)
+	}
+	return nil
+}
+
+// idSpace возвращает тип сущности, перечисление идентификаторов которого используется
+// для указанного типа (идентификаторы мастер-релизов хранятся среди ReleaseID).
+func idSpace(kind EntityKind) EntityKind {
+	if kind == EntityMaster {
+		return EntityRelease
+	}
+	return kind
+}
+
+// builtinID возвращает встроенное значение перечисления идентификаторов типа сущности
+// по ключу или 0.
+func builtinID(kind EntityKind, key string) uint8 {
+	switch kind {
+	case EntityRelease:
+		return uint8(StrToReleaseID[key])
+	case EntityArtist:
+		return uint8(StrToActorID[key])
+	case EntityLabel:
+		return uint8(StrToLabelID[key])
+	case EntityRecording:
+		return uint8(StrToRecordingID[key])
+	case EntityTrack:
+		return uint8(StrToTrackID[key])
+	case EntityWork:
+		return uint8(StrToWorkID[key])
+	case EntityMedia:
+		return uint8(StrToMediaID[key])
+	case EntityPublishing:
+		return uint8(StrToPublishingID[key])
+	}
+	return 0
+}
+
+// lookupIDLocked возвращает значение перечисления идентификаторов типа сущности по ключу
+// или 0 для неизвестного ключа. Вызывающий должен удерживать registryMu.
+func lookupIDLocked(kind EntityKind, key string) uint8 {
+	kind = idSpace(kind)
+	if id := builtinID(kind, key); id != 0 {
+		return id
+	}
+	return registeredKeys[kind][key]
+}
+
+// LookupID возвращает значение перечисления идентификаторов типа сущности (ReleaseID,
+// ActorID, LabelID и т.д.) по ключу, в том числе назначенное при регистрации пространства
+// имен, или 0 для неизвестного ключа.
+func LookupID(kind EntityKind, key string) uint8 {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return lookupIDLocked(kind, key)
+}
+
+// parseIDKey получает значение перечисления идентификаторов из ключа словаря JSON.
+// Поддерживаются также числовые ключи прежнего формата.
+func parseIDKey(kind EntityKind, key string) uint8 {
+	if id := LookupID(kind, key); id != 0 {
+		return id
+	}
+	return parseNumericID(key)
+}
+
+// registerEntityID назначает ключу первое свободное значение перечисления идентификаторов.
+// Вызывающий должен удерживать registryMu для записи.
+func registerEntityID(kind EntityKind, key string) error {
+	if lookupIDLocked(kind, key) != 0 {
+		return nil
+	}
+	ids := registeredIDs[kind]
+	if ids == nil {
+		ids = map[uint8]string{}
+		registeredIDs[kind] = ids
+		registeredKeys[kind] = map[string]uint8{}
+	}
+	for id := firstRegisteredID; id <= 255; id++ {
+		if _, ok := ids[uint8(id)]; !ok {
+			ids[uint8(id)] = key
+			registeredKeys[kind][key] = uint8(id)
+			return nil
+		}
+	}
+	return fmt.Errorf("namespace %q: too many %s identifiers", key, kind)
+}
+
+// unregisterNamespace удаляет пространство имен и назначенные ему значения перечислений.
+// Используется для очистки реестра в тестах.
+func unregisterNamespace(key string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(namespaces, key)
+	for kind, keys := range registeredKeys {
+		if id, ok := keys[key]; ok {
+			delete(keys, key)
+			delete(registeredIDs[kind], id)
+		}
+	}
+}
+
+// registeredIDKey возвращает ключ назначенного при регистрации значения перечисления.
+func registeredIDKey(kind EntityKind, id uint8) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registeredIDs[kind][id]
+}
+
+// parseNumericID разбирает ключ словаря идентификаторов в числовом виде, в котором
+// сохранялись словари без собственной сериализации.
+func parseNumericID(key string) uint8 {
+	v, err := strconv.ParseUint(key, 10, 8)
+	if err != nil || v >= firstRegisteredID {
+		return 0
+	}
+	return uint8(v)
+}
+
+// patternNamespace создает пространство имен с идентификаторами, проверяемыми idRe,
+// ссылками вида urlFormats[kind] и разбором ссылок по hostRe и paths[kind].
+func patternNamespace(key string, idRe, hostRe *regexp.Regexp,
+	urlFormats map[EntityKind]string, paths map[EntityKind]*regexp.Regexp) *Namespace {
+	ns := &Namespace{Key: key}
+	for kind := range urlFormats {
+		ns.Entities = append(ns.Entities, kind)
+	}
+	sort.Slice(ns.Entities, func(i, j int) bool { return ns.Entities[i] < ns.Entities[j] })
+	ns.Validate = func(id string) error {
+		if !idRe.MatchString(id) {
+			return fmt.Errorf("wrong format")
+		}
+		return nil
+	}
+	ns.URL = func(kind EntityKind, id string) string {
+		if format, ok := urlFormats[kind]; ok && idRe.MatchString(id) {
+			return fmt.Sprintf(format, id)
+		}
+		return ""
+	}
+	ns.ParseURL = func(u *url.URL) (EntityKind, string, bool) {
+		if !hostRe.MatchString(strings.ToLower(u.Hostname())) {
+			return 0, "", false
+		}
+		path := u.Path
+		if u.RawQuery != "" {
+			path += "?" + u.RawQuery
+		}
+		for kind, re := range paths {
+			if m := re.FindStringSubmatch(path); m != nil && idRe.MatchString(m[1]) {
+				return kind, m[1], true
+			}
+		}
+		return 0, "", false
+	}
+	return ns
+}
+
+var (
+	digitsID   = regexp.MustCompile(`^\d+$`)
+	spotifyID  = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+	qobuzID    = regexp.MustCompile(`^[0-9a-z]+$`)
+	bandcampID = regexp.MustCompile(`^[0-9a-z-]+\.bandcamp\.com(/(album|track)/[0-9a-z-]+)?$`)
+	wikidataID = regexp.MustCompile(`^Q[1-9]\d*$`)
+)
+
+func init() {
+	MustRegisterNamespace(patternNamespace("spotify_id", spotifyID,
+		regexp.MustCompile(`^open\.spotify\.com$`),
+		map[EntityKind]string{
+			EntityRelease: "https://open.spotify.com/album/%s",
+			EntityArtist:  "https://open.spotify.com/artist/%s",
+			EntityTrack:   "https://open.spotify.com/track/%s",
+		},
+		map[EntityKind]*regexp.Regexp{
+			EntityRelease: regexp.MustCompile(`^(?:/intl-[a-z]+)?/album/([^/?]+)`),
+			EntityArtist:  regexp.MustCompile(`^(?:/intl-[a-z]+)?/artist/([^/?]+)`),
+			EntityTrack:   regexp.MustCompile(`^(?:/intl-[a-z]+)?/track/([^/?]+)`),
+		}))
+	MustRegisterNamespace(patternNamespace("deezer_id", digitsID,
+		regexp.MustCompile(`^(www\.)?deezer\.com$`),
+		map[EntityKind]string{
+			EntityRelease: "https://www.deezer.com/album/%s",
+			EntityArtist:  "https://www.deezer.com/artist/%s",
+			EntityTrack:   "https://www.deezer.com/track/%s",
+		},
+		map[EntityKind]*regexp.Regexp{
+			EntityRelease: regexp.MustCompile(`^(?:/[a-z]{2})?/album/(\d+)`),
+			EntityArtist:  regexp.MustCompile(`^(?:/[a-z]{2})?/artist/(\d+)`),
+			EntityTrack:   regexp.MustCompile(`^(?:/[a-z]{2})?/track/(\d+)`),
+		}))
+	MustRegisterNamespace(patternNamespace("apple_music_id", digitsID,
+		regexp.MustCompile(`^music\.apple\.com$`),
+		map[EntityKind]string{
+			EntityRelease: "https://music.apple.com/album/%s",
+			EntityArtist:  "https://music.apple.com/artist/%s",
+			EntityTrack:   "https://music.apple.com/song/%s",
+		},
+		map[EntityKind]*regexp.Regexp{
+			EntityRelease: regexp.MustCompile(`^(?:/[a-z]{2})?/album/(?:[^/]+/)?(\d+)(?:$|\?)`),
+			EntityArtist:  regexp.MustCompile(`^(?:/[a-z]{2})?/artist/(?:[^/]+/)?(\d+)`),
+			EntityTrack:   regexp.MustCompile(`^(?:/[a-z]{2})?/song/(?:[^/]+/)?(\d+)`),
+		}))
+	MustRegisterNamespace(patternNamespace("tidal_id", digitsID,
+		regexp.MustCompile(`^(listen\.)?tidal\.com$`),
+		map[EntityKind]string{
+			EntityRelease: "https://tidal.com/browse/album/%s",
+			EntityArtist:  "https://tidal.com/browse/artist/%s",
+			EntityTrack:   "https://tidal.com/browse/track/%s",
+		},
+		map[EntityKind]*regexp.Regexp{
+			EntityRelease: regexp.MustCompile(`^(?:/browse)?/album/(\d+)`),
+			EntityArtist:  regexp.MustCompile(`^(?:/browse)?/artist/(\d+)`),
+			EntityTrack:   regexp.MustCompile(`^(?:/browse)?/track/(\d+)`),
+		}))
+	MustRegisterNamespace(patternNamespace("qobuz_id", qobuzID,
+		regexp.MustCompile(`^(www\.|open\.|play\.)?qobuz\.com$`),
+		map[EntityKind]string{
+			EntityRelease: "https://open.qobuz.com/album/%s",
+			EntityArtist:  "https://open.qobuz.com/artist/%s",
+			EntityTrack:   "https://open.qobuz.com/track/%s",
+		},
+		map[EntityKind]*regexp.Regexp{
+			EntityRelease: regexp.MustCompile(`^(?:/[a-z]{2}-[a-z]{2})?/album/(?:[^/]+/)?([0-9a-z]+)$`),
+			EntityArtist: regexp.MustCompile(
+				`^(?:/[a-z]{2}-[a-z]{2})?/(?:artist|interpreter)/(?:[^/]+/)?([0-9a-z]+)$`),
+			EntityTrack: regexp.MustCompile(`^/track/([0-9a-z]+)$`),
+		}))
+	// Идентификатором Bandcamp служит адрес страницы без схемы, так как числовые
+	// идентификаторы сервиса в ссылках не используются.
+	MustRegisterNamespace(&Namespace{
+		Key:      "bandcamp_url",
+		Entities: []EntityKind{EntityRelease, EntityArtist, EntityTrack},
+		Validate: func(id string) error {
+			if !bandcampID.MatchString(id) {
+				return fmt.Errorf("wrong format")
+			}
+			return nil
+		},
+		URL: func(kind EntityKind, id string) string {
+			if !bandcampID.MatchString(id) || bandcampKind(id) != kind {
+				return ""
+			}
+			return "https://" + id
+		},
+		ParseURL: func(u *url.URL) (EntityKind, string, bool) {
+			id := strings.ToLower(u.Hostname()) + strings.TrimSuffix(u.Path, "/")
+			if !bandcampID.MatchString(id) {
+				return 0, "", false
+			}
+			return bandcampKind(id), id, true
+		},
+	})
+	// Ссылки Wikidata не содержат типа сущности, поэтому он не определяется (0).
+	MustRegisterNamespace(&Namespace{
+		Key: "wikidata_id",
+		Entities: []EntityKind{
+			EntityRelease, EntityMaster, EntityArtist, EntityLabel, EntityRecording, EntityWork},
+		Validate: func(id string) error {
+			if !wikidataID.MatchString(id) {
+				return fmt.Errorf("wrong format")
+			}
+			return nil
+		},
+		URL: func(kind EntityKind, id string) string {
+			if !wikidataID.MatchString(id) {
+				return ""
+			}
+			return "https://www.wikidata.org/wiki/" + id
+		},
+		ParseURL: func(u *url.URL) (EntityKind, string, bool) {
+			if strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") != "wikidata.org" {
+				return 0, "", false
+			}
+			id := u.Path[strings.LastIndex(u.Path, "/")+1:]
+			return 0, id, wikidataID.MatchString(id)
+		},
+	})
+}
+
+func bandcampKind(id string) EntityKind {
+	switch {
+	case strings.Contains(id, "/album/"):
+		return EntityRelease
+	case strings.Contains(id, "/track/"):
+		return EntityTrack
+	}
+	return EntityArtist
+}
@@ -0,0 +1,162 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterNamespace(t *testing.T) {
+	require.NoError(t, RegisterNamespace(&Namespace{
+		Key:      "test_catalog_id",
+		Entities: []EntityKind{EntityRelease, EntityLabel},
+	}))
+	t.Cleanup(func() { unregisterNamespace("test_catalog_id") })
+	assert.Error(t, RegisterNamespace(&Namespace{
+		Key: "test_catalog_id", Entities: []EntityKind{EntityRelease}}))
+	assert.Error(t, RegisterNamespace(&Namespace{Key: "test_empty_id"}))
+	assert.Error(t, RegisterNamespace(&Namespace{
+		Key: "test_bad_kind_id", Entities: []EntityKind{0}}))
+
+	rid := ReleaseID(LookupID(EntityRelease, "test_catalog_id"))
+	require.NotZero(t, rid)
+	assert.Equal(t, "test_catalog_id", rid.String())
+	lid := LabelID(LookupID(EntityLabel, "test_catalog_id"))
+	require.NotZero(t, lid)
+	assert.Equal(t, "test_catalog_id", lid.String())
+	assert.Zero(t, LookupID(EntityArtist, "test_catalog_id"))
+	_, ok := StrToReleaseID["test_catalog_id"]
+	assert.False(t, ok)
+
+	ns, ok := LookupNamespace("test_catalog_id")
+	require.True(t, ok)
+	assert.True(t, ns.Supports(EntityLabel))
+	assert.False(t, ns.Supports(EntityArtist))
+	assert.Contains(t, Namespaces(EntityLabel), ns)
+
+	require.NoError(t, RegisterNamespace(&Namespace{Key: "test_temp_id", Entities: []EntityKind{EntityRelease}}))
+	unregisterNamespace("test_temp_id")
+	assert.Zero(t, LookupID(EntityRelease, "test_temp_id"))
+	_, ok = LookupNamespace("test_temp_id")
+	assert.False(t, ok)
+}
+
+func TestRegisteredNamespaceJSON(t *testing.T) {
+	rid := ReleaseID(LookupID(EntityRelease, "spotify_id"))
+	require.NotZero(t, rid)
+	stub := NewReleaseStub()
+	stub.IDs[rid] = "6dVIqQ8qmQ5GBnJ9shOYGE"
+	stub.IDs[DiscogsReleaseID] = "2528044"
+	data, err := json.Marshal(stub.IDs)
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"spotify_id":"6dVIqQ8qmQ5GBnJ9shOYGE","discogs_release_id":"2528044"}`, string(data))
+
+	ids := map[ReleaseID]string{}
+	require.NoError(t, json.Unmarshal(data, &ids))
+	assert.Equal(t, stub.IDs, ids)
+
+	tids := TrackIDs{}
+	require.NoError(t, json.Unmarshal([]byte(`{"deezer_id":"3135556"}`), &tids))
+	assert.Equal(t, "3135556", tids[TrackID(LookupID(EntityTrack, "deezer_id"))])
+}
+
+func TestLegacyNumericIDKeys(t *testing.T) {
+	ids := map[ReleaseID]string{}
+	require.NoError(t, json.Unmarshal([]byte(`{"1":"2528044","6":"12345"}`), &ids))
+	assert.Equal(t, map[ReleaseID]string{DiscogsReleaseID: "2528044", Rutracker: "12345"}, ids)
+}
+
+func TestRegisterNamespaceConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("test_concurrent_%d", i)
+			assert.NoError(t, RegisterNamespace(&Namespace{Key: key, Entities: []EntityKind{EntityTrack}}))
+			t.Cleanup(func() { unregisterNamespace(key) })
+			var tid TrackID
+			assert.NoError(t, tid.UnmarshalText([]byte(key)))
+			assert.Equal(t, key, tid.String())
+			assert.NotEmpty(t, Namespaces(EntityTrack))
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestValidateID(t *testing.T) {
+	assert.NoError(t, ValidateID("spotify_id", "6dVIqQ8qmQ5GBnJ9shOYGE"))
+	assert.Error(t, ValidateID("spotify_id", "6dVIqQ8"))
+	assert.NoError(t, ValidateID("wikidata_id", "Q1299"))
+	assert.Error(t, ValidateID("wikidata_id", "1299"))
+	assert.NoError(t, ValidateID("bandcamp_url", "artist.bandcamp.com/album/some-album"))
+	assert.Error(t, ValidateID("unknown_namespace_id", "1"))
+}
+
+func TestNamespaceURL(t *testing.T) {
+	tests := []struct {
+		key  string
+		kind EntityKind
+		id   string
+		url  string
+	}{
+		{"spotify_id", EntityRelease, "6dVIqQ8qmQ5GBnJ9shOYGE",
+			"https://open.spotify.com/album/6dVIqQ8qmQ5GBnJ9shOYGE"},
+		{"deezer_id", EntityArtist, "27", "https://www.deezer.com/artist/27"},
+		{"apple_music_id", EntityTrack, "1441164670", "https://music.apple.com/song/1441164670"},
+		{"tidal_id", EntityRelease, "77646169", "https://tidal.com/browse/album/77646169"},
+		{"qobuz_id", EntityRelease, "0060254781175", "https://open.qobuz.com/album/0060254781175"},
+		{"bandcamp_url", EntityRelease, "artist.bandcamp.com/album/some-album",
+			"https://artist.bandcamp.com/album/some-album"},
+		{"wikidata_id", EntityArtist, "Q1299", "https://www.wikidata.org/wiki/Q1299"},
+	}
+	for _, tc := range tests {
+		ns, ok := LookupNamespace(tc.key)
+		require.True(t, ok, tc.key)
+		assert.Equal(t, tc.url, ns.URL(tc.kind, tc.id), tc.key)
+		u, err := url.Parse(tc.url)
+		require.NoError(t, err)
+		kind, id, ok := ns.ParseURL(u)
+		assert.True(t, ok, tc.url)
+		assert.Equal(t, tc.id, id, tc.url)
+		if tc.key != "wikidata_id" {
+			assert.Equal(t, tc.kind, kind, tc.url)
+		}
+	}
+}
+
+func TestNamespaceParseURLVariants(t *testing.T) {
+	tests := []struct {
+		key  string
+		url  string
+		kind EntityKind
+		id   string
+	}{
+		{"apple_music_id", "https://music.apple.com/us/album/abbey-road/1441164426",
+			EntityRelease, "1441164426"},
+		{"qobuz_id", "https://www.qobuz.com/gb-en/album/abbey-road-the-beatles/0060254781175",
+			EntityRelease, "0060254781175"},
+		{"deezer_id", "https://www.deezer.com/en/track/3135556", EntityTrack, "3135556"},
+		{"spotify_id", "https://open.spotify.com/intl-de/artist/3WrFJ7ztbogyGnTHbHJFl2?si=x",
+			EntityArtist, "3WrFJ7ztbogyGnTHbHJFl2"},
+	}
+	for _, tc := range tests {
+		ns, _ := LookupNamespace(tc.key)
+		u, err := url.Parse(tc.url)
+		require.NoError(t, err)
+		kind, id, ok := ns.ParseURL(u)
+		require.True(t, ok, tc.url)
+		assert.Equal(t, tc.kind, kind, tc.url)
+		assert.Equal(t, tc.id, id, tc.url)
+	}
+	ns, _ := LookupNamespace("deezer_id")
+	u, _ := url.Parse("https://open.spotify.com/album/6dVIqQ8qmQ5GBnJ9shOYGE")
+	_, _, ok := ns.ParseURL(u)
+	assert.False(t, ok)
+}
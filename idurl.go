@@ -0,0 +1,191 @@
+package metadata
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	mbid            = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	asinID          = regexp.MustCompile(`^[0-9A-Z]{10}$`)
+	discogsHost     = regexp.MustCompile(`^(www\.)?discogs\.com$`)
+	musicbrainzHost = regexp.MustCompile(`^((beta|test)\.)?musicbrainz\.org$`)
+)
+
+func discogsPath(entity string) *regexp.Regexp {
+	return regexp.MustCompile(`^(?:/[a-z]{2})?(?:/[^/]+)?/` + entity + `/(\d+)`)
+}
+
+func musicbrainzPath(entity string) *regexp.Regexp {
+	return regexp.MustCompile(`^/` + entity + `/([0-9a-f-]{36})`)
+}
+
+func init() {
+	for _, ns := range []*Namespace{
+		patternNamespace(DiscogsReleaseID.String(), digitsID, discogsHost,
+			map[EntityKind]string{EntityRelease: "https://www.discogs.com/release/%s"},
+			map[EntityKind]*regexp.Regexp{EntityRelease: discogsPath("release")}),
+		patternNamespace(DiscogsMasterID.String(), digitsID, discogsHost,
+			map[EntityKind]string{EntityMaster: "https://www.discogs.com/master/%s"},
+			map[EntityKind]*regexp.Regexp{EntityMaster: discogsPath("master")}),
+		patternNamespace(DiscogsArtistID.String(), digitsID, discogsHost,
+			map[EntityKind]string{EntityArtist: "https://www.discogs.com/artist/%s"},
+			map[EntityKind]*regexp.Regexp{EntityArtist: discogsPath("artist")}),
+		patternNamespace(DiscogsLabelID.String(), digitsID, discogsHost,
+			map[EntityKind]string{EntityLabel: "https://www.discogs.com/label/%s"},
+			map[EntityKind]*regexp.Regexp{EntityLabel: discogsPath("label")}),
+		patternNamespace(DiscogsCompositionID.String(), digitsID, discogsHost,
+			map[EntityKind]string{EntityWork: "https://www.discogs.com/composition/%s"},
+			map[EntityKind]*regexp.Regexp{EntityWork: discogsPath("composition")}),
+		patternNamespace(MusicbrainzAlbumID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityRelease: "https://musicbrainz.org/release/%s"},
+			map[EntityKind]*regexp.Regexp{EntityRelease: musicbrainzPath("release")}),
+		patternNamespace(MusicbrainzReleaseGroupID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityMaster: "https://musicbrainz.org/release-group/%s"},
+			map[EntityKind]*regexp.Regexp{EntityMaster: musicbrainzPath("release-group")}),
+		patternNamespace(MusicbrainzArtistID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityArtist: "https://musicbrainz.org/artist/%s"},
+			map[EntityKind]*regexp.Regexp{EntityArtist: musicbrainzPath("artist")}),
+		patternNamespace(MusicbrainzLabelID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityLabel: "https://musicbrainz.org/label/%s"},
+			map[EntityKind]*regexp.Regexp{EntityLabel: musicbrainzPath("label")}),
+		patternNamespace(MusicbrainzRecordingID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityRecording: "https://musicbrainz.org/recording/%s"},
+			map[EntityKind]*regexp.Regexp{EntityRecording: musicbrainzPath("recording")}),
+		patternNamespace(MusicbrainzReleaseTrackID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityTrack: "https://musicbrainz.org/track/%s"},
+			map[EntityKind]*regexp.Regexp{EntityTrack: musicbrainzPath("track")}),
+		patternNamespace(MusicbrainzWorkID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityWork: "https://musicbrainz.org/work/%s"},
+			map[EntityKind]*regexp.Regexp{EntityWork: musicbrainzPath("work")}),
+		// теги Picard с идентификаторами MusicBrainz: только ссылки, разбор ссылок
+		// возвращает основные ключи
+		patternNamespace(MusicbrainzAlbumArtistID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityArtist: "https://musicbrainz.org/artist/%s"}, nil),
+		patternNamespace(MusicbrainzOriginalArtistID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityArtist: "https://musicbrainz.org/artist/%s"}, nil),
+		patternNamespace(MusicbrainzOriginalAlbumID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityRelease: "https://musicbrainz.org/release/%s"}, nil),
+		patternNamespace(MusicbrainzTrackID.String(), mbid, musicbrainzHost,
+			map[EntityKind]string{EntityTrack: "https://musicbrainz.org/recording/%s"}, nil),
+		patternNamespace(Rutracker.String(), digitsID, regexp.MustCompile(`^(www\.)?rutracker\.(org|net)$`),
+			map[EntityKind]string{EntityRelease: "https://rutracker.org/forum/viewtopic.php?t=%s"},
+			map[EntityKind]*regexp.Regexp{
+				EntityRelease: regexp.MustCompile(`^/forum/viewtopic\.php\?(?:.*&)?t=(\d+)`)}),
+		patternNamespace(Asin.String(), asinID, regexp.MustCompile(`^(www\.)?amazon\.[a-z.]+$`),
+			map[EntityKind]string{EntityRelease: "https://www.amazon.com/dp/%s"},
+			map[EntityKind]*regexp.Regexp{
+				EntityRelease: regexp.MustCompile(`^(?:/[^/]+)?/(?:dp|gp/product)/([0-9A-Z]{10})`)}),
+	} {
+		MustRegisterNamespace(ns)
+	}
+}
+
+// Identifier описывает идентификатор сущности во внешней БД, извлеченный из ссылки.
+// Type содержит типизированный ключ идентификатора (ReleaseID, ActorID, LabelID,
+// RecordingID, TrackID или WorkID).
+type Identifier struct {
+	Kind      EntityKind
+	Namespace string
+	Type      fmt.Stringer
+	ID        string
+}
+
+// ParseIdentifierURL определяет по ссылке на страницу внешней БД тип сущности и ее
+// идентификатор ("https://www.discogs.com/release/2528044-..." - релиз с идентификатором
+// DiscogsReleaseID "2528044").
+func ParseIdentifierURL(rawurl string) (*Identifier, error) {
+	u, err := url.Parse(strings.TrimSpace(rawurl))
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		if u, err = url.Parse("https://" + strings.TrimSpace(rawurl)); err != nil {
+			return nil, err
+		}
+	}
+	for _, ns := range allNamespaces() {
+		if ns.ParseURL == nil {
+			continue
+		}
+		if kind, id, ok := ns.ParseURL(u); ok {
+			return &Identifier{Kind: kind, Namespace: ns.Key, Type: typedID(kind, ns.Key), ID: id}, nil
+		}
+	}
+	return nil, fmt.Errorf("url %q: unknown identifier", rawurl)
+}
+
+// IdentifierURL формирует каноническую ссылку на страницу сущности во внешней БД.
+// При отсутствии правил формирования ссылки возвращается пустая строка.
+func IdentifierURL(kind EntityKind, key, id string) string {
+	if ns, ok := LookupNamespace(key); ok && ns.URL != nil && ns.Supports(kind) {
+		return ns.URL(kind, id)
+	}
+	return ""
+}
+
+func entityURL(key, id string, kinds ...EntityKind) string {
+	for _, kind := range kinds {
+		if ret := IdentifierURL(kind, key, id); ret != "" {
+			return ret
+		}
+	}
+	return ""
+}
+
+// typedID возвращает ключ пространства имен в виде значения перечисления идентификаторов
+// сущности.
+func typedID(kind EntityKind, key string) fmt.Stringer {
+	id := LookupID(kind, key)
+	switch idSpace(kind) {
+	case EntityRelease:
+		return ReleaseID(id)
+	case EntityArtist:
+		return ActorID(id)
+	case EntityLabel:
+		return LabelID(id)
+	case EntityRecording:
+		return RecordingID(id)
+	case EntityTrack:
+		return TrackID(id)
+	case EntityWork:
+		return WorkID(id)
+	case EntityMedia:
+		return MediaID(id)
+	case EntityPublishing:
+		return PublishingID(id)
+	}
+	return nil
+}
+
+// URL формирует каноническую ссылку на релиз или мастер-релиз во внешней БД.
+func (rid ReleaseID) URL(id string) string {
+	return entityURL(rid.String(), id, EntityRelease, EntityMaster)
+}
+
+// URL формирует каноническую ссылку на актора во внешней БД.
+func (aid ActorID) URL(id string) string {
+	return entityURL(aid.String(), id, EntityArtist)
+}
+
+// URL формирует каноническую ссылку на лейбл во внешней БД.
+func (lid LabelID) URL(id string) string {
+	return entityURL(lid.String(), id, EntityLabel)
+}
+
+// URL формирует каноническую ссылку на запись во внешней БД.
+func (rid RecordingID) URL(id string) string {
+	return entityURL(rid.String(), id, EntityRecording)
+}
+
+// URL формирует каноническую ссылку на трек во внешней БД.
+func (tid TrackID) URL(id string) string {
+	return entityURL(tid.String(), id, EntityTrack)
+}
+
+// URL формирует каноническую ссылку на произведение во внешней БД.
+func (wid WorkID) URL(id string) string {
+	return entityURL(wid.String(), id, EntityWork)
+}
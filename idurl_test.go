@@ -0,0 +1,94 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIdentifierURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		kind EntityKind
+		typ  interface{}
+		id   string
+	}{
+		{"https://www.discogs.com/release/2528044-Miles-Davis-Kind-Of-Blue",
+			EntityRelease, DiscogsReleaseID, "2528044"},
+		{"https://www.discogs.com/ru/release/2528044", EntityRelease, DiscogsReleaseID, "2528044"},
+		{"https://www.discogs.com/Miles-Davis-Kind-Of-Blue/release/2528044",
+			EntityRelease, DiscogsReleaseID, "2528044"},
+		{"https://www.discogs.com/master/5460-Miles-Davis-Kind-Of-Blue",
+			EntityMaster, DiscogsMasterID, "5460"},
+		{"https://www.discogs.com/artist/23755-Miles-Davis", EntityArtist, DiscogsArtistID, "23755"},
+		{"discogs.com/label/681-Columbia", EntityLabel, DiscogsLabelID, "681"},
+		{"https://musicbrainz.org/release/0f3fdb8a-6d14-4d2a-b7f6-d6e0a9ad7c47",
+			EntityRelease, MusicbrainzAlbumID, "0f3fdb8a-6d14-4d2a-b7f6-d6e0a9ad7c47"},
+		{"https://musicbrainz.org/release-group/8e8a594f-2175-38e6-a876-ac8cff1e1423",
+			EntityMaster, MusicbrainzReleaseGroupID, "8e8a594f-2175-38e6-a876-ac8cff1e1423"},
+		{"https://musicbrainz.org/artist/561d854a-6a28-4aa7-8c99-323e6ce46c2a/releases",
+			EntityArtist, MusicbrainzArtistID, "561d854a-6a28-4aa7-8c99-323e6ce46c2a"},
+		{"https://musicbrainz.org/recording/e1b3b2c2-6c5e-4f7e-9c2b-2c1e4b6f0a11",
+			EntityRecording, MusicbrainzRecordingID, "e1b3b2c2-6c5e-4f7e-9c2b-2c1e4b6f0a11"},
+		{"https://musicbrainz.org/work/f5cdaa2b-5a0d-3d7f-8c6e-2b6d7e9e1d10",
+			EntityWork, MusicbrainzWorkID, "f5cdaa2b-5a0d-3d7f-8c6e-2b6d7e9e1d10"},
+		{"https://rutracker.org/forum/viewtopic.php?t=5283571", EntityRelease, Rutracker, "5283571"},
+		{"https://www.amazon.com/dp/B000002ADT", EntityRelease, Asin, "B000002ADT"},
+		{"https://www.amazon.co.uk/Kind-Blue-Miles-Davis/dp/B000002ADT/ref=sr_1_1",
+			EntityRelease, Asin, "B000002ADT"},
+		{"https://www.amazon.de/gp/product/B000002ADT", EntityRelease, Asin, "B000002ADT"},
+	}
+	for _, tc := range tests {
+		ident, err := ParseIdentifierURL(tc.url)
+		require.NoError(t, err, tc.url)
+		assert.Equal(t, tc.kind, ident.Kind, tc.url)
+		assert.Equal(t, tc.typ, ident.Type, tc.url)
+		assert.Equal(t, tc.id, ident.ID, tc.url)
+	}
+	for _, s := range []string{
+		"https://example.com/release/1",
+		"https://www.discogs.com/search?q=miles",
+		"https://musicbrainz.org/release/not-an-mbid",
+		"::",
+	} {
+		_, err := ParseIdentifierURL(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestIdentifierURL(t *testing.T) {
+	assert.Equal(t, "https://www.discogs.com/release/2528044", DiscogsReleaseID.URL("2528044"))
+	assert.Equal(t, "https://www.discogs.com/master/5460", DiscogsMasterID.URL("5460"))
+	assert.Equal(t, "https://musicbrainz.org/release-group/8e8a594f-2175-38e6-a876-ac8cff1e1423",
+		MusicbrainzReleaseGroupID.URL("8e8a594f-2175-38e6-a876-ac8cff1e1423"))
+	assert.Equal(t, "https://rutracker.org/forum/viewtopic.php?t=5283571", Rutracker.URL("5283571"))
+	assert.Equal(t, "https://www.amazon.com/dp/B000002ADT", Asin.URL("B000002ADT"))
+	assert.Equal(t, "https://www.discogs.com/artist/23755", DiscogsArtistID.URL("23755"))
+	assert.Equal(t, "https://musicbrainz.org/label/011d1192-6f65-45bd-85c4-0400dd45693e",
+		MusicbrainzLabelID.URL("011d1192-6f65-45bd-85c4-0400dd45693e"))
+	assert.Equal(t, "https://www.discogs.com/composition/123", DiscogsCompositionID.URL("123"))
+	const artistMBID = "83d91898-7763-47d7-b03b-b92132375c47"
+	assert.Equal(t, "https://musicbrainz.org/artist/"+artistMBID, MusicbrainzAlbumArtistID.URL(artistMBID))
+	assert.Equal(t, "https://musicbrainz.org/artist/"+artistMBID, MusicbrainzOriginalArtistID.URL(artistMBID))
+	ident, err := ParseIdentifierURL(MusicbrainzAlbumArtistID.URL(artistMBID))
+	require.NoError(t, err)
+	assert.Equal(t, MusicbrainzArtistID, ident.Type)
+	const releaseMBID = "b84ee12a-09ef-421b-82de-0441a926375b"
+	assert.Equal(t, "https://musicbrainz.org/release/"+releaseMBID, MusicbrainzOriginalAlbumID.URL(releaseMBID))
+	const recordingMBID = "9f9a2b24-6fc5-4b6e-8b5c-3d5e1d0e9c8a"
+	assert.Equal(t, "https://musicbrainz.org/recording/"+recordingMBID, MusicbrainzTrackID.URL(recordingMBID))
+	assert.Equal(t, "https://open.spotify.com/track/3n3Ppam7vgaVa1iaRUc9Lp",
+		TrackID(LookupID(EntityTrack, "spotify_id")).URL("3n3Ppam7vgaVa1iaRUc9Lp"))
+	assert.Empty(t, AccurateRip.URL("123"))
+	assert.Empty(t, DiscogsReleaseID.URL("not-a-number"))
+
+	stub := NewReleaseStub()
+	stub.IDs[DiscogsReleaseID] = "2528044"
+	for k, v := range stub.IDs {
+		ident, err := ParseIdentifierURL(k.URL(v))
+		require.NoError(t, err)
+		assert.Equal(t, k, ident.Type)
+		assert.Equal(t, v, ident.ID)
+	}
+}
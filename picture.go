@@ -67,10 +67,13 @@ type PictureMetadata struct {
 // TODO: реализовать методы Clean() и IsEmpty().
 type PictureInAudio struct {
 	*PictureMetadata `json:"pict_meta,omitempty"`
-	PictType         PictType `json:"pict_type"`
-	Notes            string   `json:"description,omitempty"`
-	CoverURL         string   `json:"cover_url,omitempty"`
-	Data             []byte   `json:"data,omitempty"`
+	PictType         PictType           `json:"pict_type"`
+	Notes            string             `json:"description,omitempty"`
+	CoverURL         string             `json:"cover_url,omitempty"`
+	Data             []byte             `json:"data,omitempty"`
+	Hash             string             `json:"hash,omitempty"`
+	Derived          *PictureDerivation `json:"derived,omitempty"`
+	PHash            string             `json:"phash,omitempty"`
 }
 
 // TODO: посмотреть как извлекать фото артистов из online БД.
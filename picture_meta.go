@@ -0,0 +1,138 @@
+package metadata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // регистрация декодера GIF
+	_ "image/jpeg" // регистрация декодера JPEG
+	_ "image/png"  // регистрация декодера PNG
+
+	_ "golang.org/x/image/bmp"  // регистрация декодера BMP
+	_ "golang.org/x/image/webp" // регистрация декодера WebP
+)
+
+// MIME-типы изображений, поддерживаемые ReadPictureMetadata.
+var pictureMimeTypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+	"bmp":  "image/bmp",
+	"webp": "image/webp",
+}
+
+// ErrNoPictureData возвращается при отсутствии данных изображения.
+var ErrNoPictureData = errors.New("picture: no data")
+
+// ErrPictureDimensions возвращается, если количество пикселей изображения превышает
+// MaxPicturePixels.
+var ErrPictureDimensions = errors.New("picture: dimensions exceed limit")
+
+// MaxPicturePixels ограничивает количество пикселей декодируемого изображения
+// (по умолчанию 64 мегапикселя, например, 8192x8192).
+var MaxPicturePixels = 64 << 20
+
+// decodePictureConfig считывает заголовок изображения и проверяет его размеры до
+// декодирования данных.
+func decodePictureConfig(data []byte) (image.Config, string, error) {
+	if len(data) == 0 {
+		return image.Config{}, "", ErrNoPictureData
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return cfg, format, fmt.Errorf("picture: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > MaxPicturePixels/cfg.Height {
+		return cfg, format, fmt.Errorf("%w: %dx%d", ErrPictureDimensions, cfg.Width, cfg.Height)
+	}
+	return cfg, format, nil
+}
+
+// decodePicture декодирует изображение после проверки его размеров.
+func decodePicture(data []byte) (image.Image, string, error) {
+	if _, _, err := decodePictureConfig(data); err != nil {
+		return nil, "", err
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, format, fmt.Errorf("picture: corrupt %s data: %w", format, err)
+	}
+	return img, format, nil
+}
+
+// ReadPictureMetadata определяет по содержимому изображения JPEG, PNG, GIF, BMP или WebP
+// его MIME-тип, размеры, глубину цвета, количество цветов палитры и размер данных.
+// Изображение декодируется полностью, поэтому поврежденные данные отвергаются.
+// Изображения с количеством пикселей более MaxPicturePixels не декодируются.
+func ReadPictureMetadata(data []byte) (*PictureMetadata, error) {
+	cfg, format, err := decodePictureConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	mimeType, ok := pictureMimeTypes[format]
+	if !ok {
+		return nil, fmt.Errorf("picture: unsupported format %q", format)
+	}
+	if _, _, err := decodePicture(data); err != nil {
+		return nil, err
+	}
+	ret := &PictureMetadata{
+		MimeType: mimeType,
+		Width:    uint32(cfg.Width),
+		Height:   uint32(cfg.Height),
+		Size:     uint32(len(data)),
+	}
+	ret.ColorDepth, ret.Colors = colorDepth(cfg.ColorModel)
+	if format == "png" {
+		if depth := pngColorDepth(data); depth != 0 {
+			ret.ColorDepth = depth
+		}
+	}
+	return ret, nil
+}
+
+// colorDepth возвращает количество бит на пиксель для цветовой модели и количество
+// цветов для палитры.
+func colorDepth(model color.Model) (uint32, uint32) {
+	if palette, ok := model.(color.Palette); ok {
+		depth := uint32(1)
+		for 1<<depth < len(palette) {
+			depth++
+		}
+		return depth, uint32(len(palette))
+	}
+	switch model {
+	case color.GrayModel, color.AlphaModel:
+		return 8, 0
+	case color.Gray16Model, color.Alpha16Model:
+		return 16, 0
+	case color.YCbCrModel:
+		return 24, 0
+	case color.RGBA64Model, color.NRGBA64Model:
+		return 64, 0
+	}
+	return 32, 0
+}
+
+// pngColorDepth вычисляет количество бит на пиксель по заголовку IHDR изображения PNG.
+func pngColorDepth(data []byte) uint32 {
+	const ihdrOffset = 8 + 8 // сигнатура, длина и тип блока
+	if len(data) < ihdrOffset+13 || string(data[12:16]) != "IHDR" {
+		return 0
+	}
+	bitDepth := uint32(data[ihdrOffset+8])
+	channels := map[byte]uint32{0: 1, 2: 3, 3: 1, 4: 2, 6: 4}[data[ihdrOffset+9]]
+	return bitDepth * channels
+}
+
+// FillMetadata заполняет PictureMetadata по данным изображения.
+func (pia *PictureInAudio) FillMetadata() error {
+	meta, err := ReadPictureMetadata(pia.Data)
+	if err != nil {
+		return err
+	}
+	pia.PictureMetadata = meta
+	return nil
+}
@@ -0,0 +1,117 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/bmp"
+)
+
+// webp1x1 - изображение WebP (lossless) размером 1x1.
+const webp1x1 = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	return img
+}
+
+func encodeTestImage(t *testing.T, format string, w, h int) []byte {
+	var buf bytes.Buffer
+	img := testImage(w, h)
+	switch format {
+	case "jpeg":
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	case "png":
+		require.NoError(t, png.Encode(&buf, img))
+	case "gif":
+		require.NoError(t, gif.Encode(&buf, img, &gif.Options{NumColors: 256}))
+	case "bmp":
+		require.NoError(t, bmp.Encode(&buf, img))
+	}
+	return buf.Bytes()
+}
+
+func TestReadPictureMetadata(t *testing.T) {
+	tests := []struct {
+		format   string
+		mimeType string
+		depth    uint32
+	}{
+		{"jpeg", "image/jpeg", 24},
+		{"png", "image/png", 24},
+		{"gif", "image/gif", 8},
+		{"bmp", "image/bmp", 32},
+	}
+	for _, tc := range tests {
+		data := encodeTestImage(t, tc.format, 40, 30)
+		meta, err := ReadPictureMetadata(data)
+		require.NoError(t, err, tc.format)
+		assert.Equal(t, tc.mimeType, meta.MimeType, tc.format)
+		assert.Equal(t, uint32(40), meta.Width, tc.format)
+		assert.Equal(t, uint32(30), meta.Height, tc.format)
+		assert.Equal(t, tc.depth, meta.ColorDepth, tc.format)
+		assert.Equal(t, uint32(len(data)), meta.Size, tc.format)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(webp1x1)
+	require.NoError(t, err)
+	meta, err := ReadPictureMetadata(data)
+	require.NoError(t, err)
+	assert.Equal(t, "image/webp", meta.MimeType)
+	assert.Equal(t, uint32(1), meta.Width)
+}
+
+func TestReadPictureMetadataPalette(t *testing.T) {
+	img := image.NewPaletted(image.Rect(0, 0, 4, 4), palette.Plan9[:16])
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	meta, err := ReadPictureMetadata(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4), meta.ColorDepth)
+	assert.Equal(t, uint32(16), meta.Colors)
+}
+
+func TestReadPictureMetadataCorrupt(t *testing.T) {
+	_, err := ReadPictureMetadata(nil)
+	assert.ErrorIs(t, err, ErrNoPictureData)
+	_, err = ReadPictureMetadata([]byte("not an image"))
+	assert.Error(t, err)
+	data := encodeTestImage(t, "jpeg", 64, 64)
+	_, err = ReadPictureMetadata(data[:len(data)/2])
+	assert.Error(t, err)
+	data = encodeTestImage(t, "png", 64, 64)
+	_, err = ReadPictureMetadata(data[:len(data)-20])
+	assert.Error(t, err)
+}
+
+func TestReadPictureMetadataDimensionLimit(t *testing.T) {
+	defer func(limit int) { MaxPicturePixels = limit }(MaxPicturePixels)
+	MaxPicturePixels = 32 * 32
+	_, err := ReadPictureMetadata(encodeTestImage(t, "png", 32, 32))
+	assert.NoError(t, err)
+	_, err = ReadPictureMetadata(encodeTestImage(t, "png", 64, 64))
+	assert.ErrorIs(t, err, ErrPictureDimensions)
+	_, err = ReadPictureMetadata(encodeTestImage(t, "jpeg", 1000, 2))
+	assert.ErrorIs(t, err, ErrPictureDimensions)
+}
+
+func TestPictureInAudioFillMetadata(t *testing.T) {
+	pia := &PictureInAudio{PictType: PictTypeCoverFront, Data: encodeTestImage(t, "png", 10, 10)}
+	require.NoError(t, pia.FillMetadata())
+	assert.Equal(t, "image/png", pia.MimeType)
+	assert.Error(t, (&PictureInAudio{}).FillMetadata())
+}
@@ -0,0 +1,190 @@
+package metadata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// Минимальное качество JPEG, до которого снижается качество при ограничении размера данных.
+const minJPEGQuality = 40
+
+// ErrPictureTooLarge возвращается, если изображение не удается уложить в заданный размер данных.
+var ErrPictureTooLarge = errors.New("picture: can't fit byte limit")
+
+// CoverPolicy описывает требования к варианту изображения: максимальный размер большей
+// стороны в пикселях, максимальный размер данных в байтах, формат ("jpeg" или "png")
+// и качество сжатия JPEG. Нулевые значения ограничений означают их отсутствие.
+type CoverPolicy struct {
+	Name     string `json:"name"`
+	MaxSide  uint32 `json:"max_side,omitempty"`
+	MaxBytes uint32 `json:"max_bytes,omitempty"`
+	Format   string `json:"format,omitempty"`
+	Quality  int    `json:"quality,omitempty"`
+}
+
+// Типовые требования к вариантам обложки.
+var (
+	PlayerCoverPolicy  = CoverPolicy{Name: "player", MaxSide: 500, Format: "jpeg", Quality: 85}
+	ArchiveCoverPolicy = CoverPolicy{Name: "archive", MaxSide: 1400, Format: "jpeg", Quality: 92}
+	APICCoverPolicy    = CoverPolicy{Name: "apic", MaxSide: 1000, MaxBytes: 200 << 10, Format: "jpeg", Quality: 90}
+)
+
+// PictureDerivation описывает происхождение производного изображения: хеш исходного
+// изображения и наименование примененных требований.
+type PictureDerivation struct {
+	Source string `json:"source"`
+	Policy string `json:"policy"`
+}
+
+// fitSize вычисляет размеры изображения, вписанного в квадрат со стороной maxSide, с
+// сохранением пропорций. Изображение не увеличивается.
+func fitSize(width, height int, maxSide uint32) (int, int) {
+	side := int(maxSide)
+	if side == 0 || (width <= side && height <= side) {
+		return width, height
+	}
+	if width >= height {
+		h := (height*side + width/2) / width
+		if h == 0 {
+			h = 1
+		}
+		return side, h
+	}
+	w := (width*side + height/2) / height
+	if w == 0 {
+		w = 1
+	}
+	return w, side
+}
+
+func scaleImage(src image.Image, width, height int) image.Image {
+	b := src.Bounds()
+	if b.Dx() == width && b.Dy() == height {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
+
+func encodeImage(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "", "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case "png":
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	default:
+		return nil, fmt.Errorf("picture: unsupported output format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Derive создает вариант изображения в соответствии с требованиями policy. При превышении
+// ограничения размера данных сначала снижается качество JPEG, затем уменьшаются размеры
+// изображения. Результат содержит обновленные PictureMetadata и сведения о происхождении.
+// Изображения с количеством пикселей более MaxPicturePixels не обрабатываются.
+func (pia *PictureInAudio) Derive(policy CoverPolicy) (*PictureInAudio, error) {
+	src, _, err := decodePicture(pia.Data)
+	if err != nil {
+		return nil, err
+	}
+	quality := policy.Quality
+	if quality == 0 {
+		quality = jpeg.DefaultQuality
+	}
+	width, height := fitSize(src.Bounds().Dx(), src.Bounds().Dy(), policy.MaxSide)
+	img := scaleImage(src, width, height)
+	data, err := encodeImage(img, policy.Format, quality)
+	for err == nil && policy.MaxBytes != 0 && uint32(len(data)) > policy.MaxBytes {
+		switch {
+		case policy.Format != "png" && quality > minJPEGQuality:
+			quality -= 10
+			if quality < minJPEGQuality {
+				quality = minJPEGQuality
+			}
+		case width > 16 && height > 16:
+			width, height = width*85/100, height*85/100
+			img = scaleImage(src, width, height)
+		default:
+			return nil, ErrPictureTooLarge
+		}
+		data, err = encodeImage(img, policy.Format, quality)
+	}
+	if err != nil {
+		return nil, err
+	}
+	meta, err := ReadPictureMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+	ret := &PictureInAudio{
+		PictureMetadata: meta,
+		PictType:        pia.PictType,
+		Notes:           pia.Notes,
+		Data:            data,
+		Derived:         &PictureDerivation{Source: pia.ComputeHash(), Policy: policy.Name},
+	}
+	ret.ComputeHash()
+	return ret, nil
+}
+
+// Variants создает варианты изображения для каждого из перечня требований.
+func (pia *PictureInAudio) Variants(policies ...CoverPolicy) ([]*PictureInAudio, error) {
+	ret := make([]*PictureInAudio, 0, len(policies))
+	for _, policy := range policies {
+		variant, err := pia.Derive(policy)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", policy.Name, err)
+		}
+		ret = append(ret, variant)
+	}
+	return ret, nil
+}
+
+// IsDerived проверяет, является ли изображение производным от другого.
+func (pia *PictureInAudio) IsDerived() bool {
+	return pia.Derived != nil
+}
+
+// Variant возвращает производное изображение для требований с указанным наименованием
+// из перечня изображений или nil.
+func Variant(pictures []*PictureInAudio, source *PictureInAudio, policy string) *PictureInAudio {
+	hash := source.ComputeHash()
+	for _, pia := range pictures {
+		if pia.Derived != nil && pia.Derived.Policy == policy && pia.Derived.Source == hash {
+			return pia
+		}
+	}
+	return nil
+}
+
+// AddCoverVariants создает варианты лицевой обложки релиза для перечня требований и
+// добавляет их в перечень изображений релиза. Уже существующие варианты не пересоздаются.
+func (r *Release) AddCoverVariants(policies ...CoverPolicy) error {
+	cover := r.Cover()
+	if cover == nil {
+		return nil
+	}
+	for _, policy := range policies {
+		if Variant(r.Pictures, cover, policy.Name) != nil {
+			continue
+		}
+		variant, err := cover.Derive(policy)
+		if err != nil {
+			return fmt.Errorf("policy %q: %w", policy.Name, err)
+		}
+		r.Pictures = append(r.Pictures, variant)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package metadata
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFitSize(t *testing.T) {
+	w, h := fitSize(1000, 800, 500)
+	assert.Equal(t, 500, w)
+	assert.Equal(t, 400, h)
+	w, h = fitSize(600, 1200, 500)
+	assert.Equal(t, 250, w)
+	assert.Equal(t, 500, h)
+	w, h = fitSize(300, 200, 500)
+	assert.Equal(t, 300, w)
+	assert.Equal(t, 200, h)
+	w, h = fitSize(300, 200, 0)
+	assert.Equal(t, 300, w)
+	assert.Equal(t, 200, h)
+}
+
+func TestPictureDerive(t *testing.T) {
+	pia := &PictureInAudio{PictType: PictTypeCoverFront, Data: encodeTestImage(t, "png", 1000, 800)}
+	variant, err := pia.Derive(PlayerCoverPolicy)
+	require.NoError(t, err)
+	assert.Equal(t, PictTypeCoverFront, variant.PictType)
+	require.NotNil(t, variant.PictureMetadata)
+	assert.Equal(t, "image/jpeg", variant.MimeType)
+	assert.Equal(t, uint32(500), variant.Width)
+	assert.Equal(t, uint32(400), variant.Height)
+	assert.Equal(t, uint32(len(variant.Data)), variant.Size)
+	require.True(t, variant.IsDerived())
+	assert.Equal(t, pia.Hash, variant.Derived.Source)
+	assert.Equal(t, "player", variant.Derived.Policy)
+	assert.Equal(t, PictureHash(variant.Data), variant.Hash)
+
+	_, err = (&PictureInAudio{}).Derive(PlayerCoverPolicy)
+	assert.ErrorIs(t, err, ErrNoPictureData)
+	_, err = pia.Derive(CoverPolicy{Name: "tiff", Format: "tiff"})
+	assert.Error(t, err)
+
+	defer func(limit int) { MaxPicturePixels = limit }(MaxPicturePixels)
+	MaxPicturePixels = 1000
+	_, err = pia.Derive(PlayerCoverPolicy)
+	assert.ErrorIs(t, err, ErrPictureDimensions)
+}
+
+func TestPictureDeriveMaxBytes(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 400))
+	rand.New(rand.NewSource(1)).Read(img.Pix)
+	data, err := encodeImage(img, "png", 0)
+	require.NoError(t, err)
+	pia := &PictureInAudio{PictType: PictTypeCoverFront, Data: data}
+	policy := CoverPolicy{Name: "small", MaxBytes: 8 << 10, Format: "jpeg", Quality: 95}
+	variant, err := pia.Derive(policy)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(variant.Data), 8<<10)
+	assert.Equal(t, variant.Width, variant.Height)
+
+	_, err = pia.Derive(CoverPolicy{Name: "tiny", MaxBytes: 100, Format: "png"})
+	assert.ErrorIs(t, err, ErrPictureTooLarge)
+}
+
+func TestReleaseAddCoverVariants(t *testing.T) {
+	r := NewRelease()
+	require.NoError(t, r.AddCoverVariants(PlayerCoverPolicy))
+	assert.Empty(t, r.Pictures)
+
+	cover := &PictureInAudio{PictType: PictTypeCoverFront, Data: encodeTestImage(t, "jpeg", 1600, 1600)}
+	r.Pictures = append(r.Pictures, cover)
+	require.NoError(t, r.AddCoverVariants(PlayerCoverPolicy, ArchiveCoverPolicy))
+	require.Len(t, r.Pictures, 3)
+	assert.Same(t, cover, r.Cover())
+	archive := Variant(r.Pictures, cover, "archive")
+	require.NotNil(t, archive)
+	assert.Equal(t, uint32(1400), archive.Width)
+
+	require.NoError(t, r.AddCoverVariants(PlayerCoverPolicy))
+	assert.Len(t, r.Pictures, 3)
+}
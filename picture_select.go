@@ -0,0 +1,217 @@
+package metadata
+
+import (
+	"image"
+	"math/bits"
+	"sort"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+// CoverRules описывает правила оценки обложек: веса предпочтения высокого разрешения,
+// квадратной формы, встроенного изображения перед внешней ссылкой и большего размера
+// данных. PreferredSide задает размер меньшей стороны, начиная с которого разрешение
+// считается достаточным, а MinSide - минимальный размер, ниже которого обложка
+// выбирается только при отсутствии других.
+type CoverRules struct {
+	ResolutionWeight float64 `json:"resolution_weight"`
+	SquareWeight     float64 `json:"square_weight"`
+	EmbeddedWeight   float64 `json:"embedded_weight"`
+	SizeWeight       float64 `json:"size_weight"`
+	PreferredSide    uint32  `json:"preferred_side,omitempty"`
+	MinSide          uint32  `json:"min_side,omitempty"`
+}
+
+// DefaultCoverRules содержит правила оценки обложек по умолчанию.
+var DefaultCoverRules = CoverRules{
+	ResolutionWeight: 4.,
+	SquareWeight:     2.,
+	EmbeddedWeight:   1.,
+	SizeWeight:       1.,
+	PreferredSide:    1400,
+	MinSide:          300,
+}
+
+// CoverScore хранит оценку обложки.
+type CoverScore struct {
+	Picture *PictureInAudio
+	Score   float64
+}
+
+func pictureSides(pia *PictureInAudio) (uint32, uint32) {
+	if pia.PictureMetadata == nil {
+		return 0, 0
+	}
+	if pia.Width < pia.Height {
+		return pia.Width, pia.Height
+	}
+	return pia.Height, pia.Width
+}
+
+func pictureSize(pia *PictureInAudio) uint32 {
+	if pia.PictureMetadata != nil && pia.Size != 0 {
+		return pia.Size
+	}
+	return uint32(len(pia.Data))
+}
+
+// IsEmbedded проверяет, доступны ли данные изображения локально (непосредственно или
+// по хешу в хранилище), в отличие от изображения по внешней ссылке.
+func (pia *PictureInAudio) IsEmbedded() bool {
+	return len(pia.Data) > 0 || pia.Hash != ""
+}
+
+// Score оценивает обложку с учетом максимального размера данных среди кандидатов.
+func (rules CoverRules) Score(pia *PictureInAudio, maxSize uint32) float64 {
+	var ret float64
+	minSide, maxSide := pictureSides(pia)
+	if minSide > 0 {
+		res := 1.
+		if rules.PreferredSide > 0 && minSide < rules.PreferredSide {
+			res = float64(minSide) / float64(rules.PreferredSide)
+		}
+		ret += rules.ResolutionWeight * res
+		ret += rules.SquareWeight * float64(minSide) / float64(maxSide)
+	}
+	if pia.IsEmbedded() {
+		ret += rules.EmbeddedWeight
+	}
+	if maxSize > 0 {
+		ret += rules.SizeWeight * float64(pictureSize(pia)) / float64(maxSize)
+	}
+	return ret
+}
+
+// ScoreCovers оценивает лицевые обложки из перечня изображений и возвращает их в порядке
+// убывания оценки. Производные изображения не рассматриваются. Обложки с меньшей
+// стороной менее MinSide помещаются в конец перечня.
+func (rules CoverRules) ScoreCovers(pictures []*PictureInAudio) []*CoverScore {
+	var candidates []*PictureInAudio
+	var maxSize uint32
+	for _, pia := range pictures {
+		if pia.PictType != PictTypeCoverFront || pia.IsDerived() {
+			continue
+		}
+		candidates = append(candidates, pia)
+		if size := pictureSize(pia); size > maxSize {
+			maxSize = size
+		}
+	}
+	ret := make([]*CoverScore, 0, len(candidates))
+	for _, pia := range candidates {
+		ret = append(ret, &CoverScore{Picture: pia, Score: rules.Score(pia, maxSize)})
+	}
+	small := func(cs *CoverScore) bool {
+		minSide, _ := pictureSides(cs.Picture)
+		return minSide > 0 && minSide < rules.MinSide
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		if small(ret[i]) != small(ret[j]) {
+			return !small(ret[i])
+		}
+		return ret[i].Score > ret[j].Score
+	})
+	return ret
+}
+
+// SelectCover возвращает лучшую по правилам лицевую обложку или nil.
+func (rules CoverRules) SelectCover(pictures []*PictureInAudio) *PictureInAudio {
+	if scores := rules.ScoreCovers(pictures); len(scores) > 0 {
+		return scores[0].Picture
+	}
+	return nil
+}
+
+// PerceptualHash вычисляет разностный перцептивный хеш (dHash) изображения, устойчивый
+// к изменению размеров и степени сжатия. Изображения с количеством пикселей более
+// MaxPicturePixels не обрабатываются.
+func PerceptualHash(data []byte) (uint64, error) {
+	src, _, err := decodePicture(data)
+	if err != nil {
+		return 0, err
+	}
+	gray := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.ApproxBiLinear.Scale(gray, gray.Bounds(), src, src.Bounds(), draw.Src, nil)
+	var ret uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			ret <<= 1
+			if gray.GrayAt(x, y).Y > gray.GrayAt(x+1, y).Y {
+				ret |= 1
+			}
+		}
+	}
+	return ret, nil
+}
+
+// HammingDistance возвращает количество различающихся бит перцептивных хешей.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// ComputePerceptualHash вычисляет и сохраняет перцептивный хеш изображения. Если данные
+// отсутствуют, используется ранее сохраненное значение.
+func (pia *PictureInAudio) ComputePerceptualHash() (uint64, error) {
+	if len(pia.Data) > 0 && pia.PHash == "" {
+		hash, err := PerceptualHash(pia.Data)
+		if err != nil {
+			return 0, err
+		}
+		pia.PHash = strconv.FormatUint(hash, 16)
+	}
+	if pia.PHash == "" {
+		return 0, ErrNoPictureData
+	}
+	return strconv.ParseUint(pia.PHash, 16, 64)
+}
+
+// DefaultPHashDistance - максимальное расстояние Хэмминга перцептивных хешей, при котором
+// изображения считаются одинаковыми.
+const DefaultPHashDistance = 10
+
+// SimilarPicture проверяет визуальное совпадение изображений по перцептивному хешу.
+func (pia *PictureInAudio) SimilarPicture(other *PictureInAudio, maxDistance int) bool {
+	a, err := pia.ComputePerceptualHash()
+	if err != nil {
+		return false
+	}
+	b, err := other.ComputePerceptualHash()
+	if err != nil {
+		return false
+	}
+	return HammingDistance(a, b) <= maxDistance
+}
+
+// DuplicateCovers группирует визуально совпадающие лицевые обложки. Возвращаются только
+// группы из двух и более изображений. Изображения без данных и перцептивного хеша
+// не рассматриваются.
+func DuplicateCovers(pictures []*PictureInAudio, maxDistance int) [][]*PictureInAudio {
+	var groups [][]*PictureInAudio
+	for _, pia := range pictures {
+		if pia.PictType != PictTypeCoverFront {
+			continue
+		}
+		if _, err := pia.ComputePerceptualHash(); err != nil {
+			continue
+		}
+		found := false
+		for i, group := range groups {
+			if group[0].SimilarPicture(pia, maxDistance) {
+				groups[i] = append(group, pia)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, []*PictureInAudio{pia})
+		}
+	}
+	var ret [][]*PictureInAudio
+	for _, group := range groups {
+		if len(group) > 1 {
+			ret = append(ret, group)
+		}
+	}
+	return ret
+}
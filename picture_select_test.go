@@ -0,0 +1,105 @@
+package metadata
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectCover(t *testing.T) {
+	embedded := &PictureInAudio{
+		PictType:        PictTypeCoverFront,
+		Data:            []byte("JPEG"),
+		PictureMetadata: &PictureMetadata{Width: 500, Height: 500, Size: 60000}}
+	discogs := &PictureInAudio{
+		PictType:        PictTypeCoverFront,
+		CoverURL:        "https://i.discogs.com/cover.jpg",
+		PictureMetadata: &PictureMetadata{Width: 600, Height: 600}}
+	caa := &PictureInAudio{
+		PictType:        PictTypeCoverFront,
+		CoverURL:        "https://coverartarchive.org/release/1/front.jpg",
+		PictureMetadata: &PictureMetadata{Width: 1500, Height: 1500}}
+	wide := &PictureInAudio{
+		PictType:        PictTypeCoverFront,
+		CoverURL:        "https://example.com/wide.jpg",
+		PictureMetadata: &PictureMetadata{Width: 3000, Height: 1400}}
+	small := &PictureInAudio{
+		PictType:        PictTypeCoverFront,
+		Data:            []byte("JPEG"),
+		PictureMetadata: &PictureMetadata{Width: 200, Height: 200, Size: 90000}}
+	back := &PictureInAudio{
+		PictType:        PictTypeCoverBack,
+		Data:            []byte("JPEG"),
+		PictureMetadata: &PictureMetadata{Width: 3000, Height: 3000}}
+
+	pictures := []*PictureInAudio{small, back, embedded, discogs, caa, wide}
+	assert.Same(t, caa, DefaultCoverRules.SelectCover(pictures))
+	scores := DefaultCoverRules.ScoreCovers(pictures)
+	require.Len(t, scores, 5)
+	assert.Same(t, small, scores[4].Picture)
+
+	// при равном разрешении встроенная обложка предпочтительнее внешней
+	assert.Same(t, embedded, DefaultCoverRules.SelectCover([]*PictureInAudio{
+		{PictType: PictTypeCoverFront, CoverURL: "https://example.com/1.jpg",
+			PictureMetadata: &PictureMetadata{Width: 500, Height: 500}},
+		embedded}))
+
+	rules := DefaultCoverRules
+	rules.EmbeddedWeight = 10.
+	assert.Same(t, embedded, rules.SelectCover(pictures))
+
+	assert.Same(t, small, DefaultCoverRules.SelectCover([]*PictureInAudio{small, back}))
+	assert.Nil(t, DefaultCoverRules.SelectCover([]*PictureInAudio{back}))
+}
+
+func TestReleaseCoverSkipsDerived(t *testing.T) {
+	r := NewRelease()
+	cover := &PictureInAudio{PictType: PictTypeCoverFront, Data: encodeTestImage(t, "jpeg", 800, 800)}
+	require.NoError(t, cover.FillMetadata())
+	r.Pictures = append(r.Pictures, cover)
+	require.NoError(t, r.AddCoverVariants(PlayerCoverPolicy))
+	assert.Same(t, cover, r.Cover())
+}
+
+func TestPerceptualHash(t *testing.T) {
+	_, err := PerceptualHash(nil)
+	assert.ErrorIs(t, err, ErrNoPictureData)
+	limit := MaxPicturePixels
+	MaxPicturePixels = 1000
+	_, err = PerceptualHash(encodeTestImage(t, "png", 256, 256))
+	MaxPicturePixels = limit
+	assert.ErrorIs(t, err, ErrPictureDimensions)
+
+	big := &PictureInAudio{PictType: PictTypeCoverFront, Data: encodeTestImage(t, "png", 256, 256)}
+	variant, err := big.Derive(CoverPolicy{Name: "small", MaxSide: 100, Quality: 60})
+	require.NoError(t, err)
+	img := testImage(256, 256)
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 256; y++ {
+			img.Set(x, y, color.RGBA{uint8(255 - x), uint8(255 - y), 128, 255})
+		}
+	}
+	data, err := encodeImage(img, "png", 0)
+	require.NoError(t, err)
+	other := &PictureInAudio{PictType: PictTypeCoverFront, Data: data}
+	remote := &PictureInAudio{PictType: PictTypeCoverFront, CoverURL: "https://example.com/1.jpg"}
+
+	a, err := big.ComputePerceptualHash()
+	require.NoError(t, err)
+	assert.NotEmpty(t, big.PHash)
+	b, err := PerceptualHash(variant.Data)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, HammingDistance(a, b), DefaultPHashDistance)
+	assert.True(t, big.SimilarPicture(variant, DefaultPHashDistance))
+	assert.False(t, big.SimilarPicture(remote, DefaultPHashDistance))
+
+	// хеш сохраняется и без данных изображения
+	stored := &PictureInAudio{PictType: PictTypeCoverFront, PHash: big.PHash}
+	assert.True(t, stored.SimilarPicture(big, 0))
+
+	groups := DuplicateCovers([]*PictureInAudio{big, other, remote, variant}, DefaultPHashDistance)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []*PictureInAudio{big, variant}, groups[0])
+}
@@ -0,0 +1,330 @@
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrPictureNotFound возвращается хранилищем при отсутствии изображения с указанным хешем.
+var ErrPictureNotFound = errors.New("picture: not found in store")
+
+// PictureHash возвращает хеш содержимого изображения (SHA-256 в шестнадцатеричном виде).
+func PictureHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeHash вычисляет и сохраняет хеш данных изображения, если он еще не вычислен.
+// Если данные отсутствуют, возвращается ранее сохраненный хеш.
+func (pia *PictureInAudio) ComputeHash() string {
+	if pia.Hash == "" && len(pia.Data) > 0 {
+		pia.Hash = PictureHash(pia.Data)
+	}
+	return pia.Hash
+}
+
+// pictureKey возвращает ключ сравнения изображения: тип и хеш содержимого или, при
+// отсутствии данных, тип и внешнюю ссылку. Объект изображения не изменяется.
+func pictureKey(pia *PictureInAudio) string {
+	hash := pia.Hash
+	if hash == "" && len(pia.Data) > 0 {
+		hash = PictureHash(pia.Data)
+	}
+	switch {
+	case hash != "":
+		return fmt.Sprintf("%d:hash:%s", pia.PictType, hash)
+	case pia.CoverURL != "":
+		return fmt.Sprintf("%d:url:%s", pia.PictType, pia.CoverURL)
+	}
+	return ""
+}
+
+// SamePicture проверяет совпадение содержимого (или внешней ссылки) и типа изображений.
+func (pia *PictureInAudio) SamePicture(other *PictureInAudio) bool {
+	key := pictureKey(pia)
+	return key != "" && key == pictureKey(other)
+}
+
+// DedupPictures удаляет из перечня изображения, совпадающие по содержимому (или ссылке)
+// и типу с предшествующими, и возвращает перечень без повторов.
+func DedupPictures(pictures []*PictureInAudio) []*PictureInAudio {
+	var ret []*PictureInAudio
+	seen := map[string]bool{}
+	for _, pia := range pictures {
+		pia.ComputeHash()
+		key := pictureKey(pia)
+		if key != "" && seen[key] {
+			continue
+		}
+		seen[key] = true
+		ret = append(ret, pia)
+	}
+	return ret
+}
+
+// PictureStore описывает хранилище данных изображений, адресуемых хешем содержимого.
+type PictureStore interface {
+	Put(data []byte) (string, error)
+	Get(hash string) ([]byte, error)
+}
+
+// MemoryPictureStore хранит данные изображений в памяти.
+type MemoryPictureStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewMemoryPictureStore создает объект MemoryPictureStore.
+func NewMemoryPictureStore() *MemoryPictureStore {
+	return &MemoryPictureStore{blobs: map[string][]byte{}}
+}
+
+// Put сохраняет данные изображения и возвращает их хеш.
+func (ms *MemoryPictureStore) Put(data []byte) (string, error) {
+	hash := PictureHash(data)
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.blobs[hash]; !ok {
+		ms.blobs[hash] = append([]byte(nil), data...)
+	}
+	return hash, nil
+}
+
+// Get возвращает данные изображения по хешу.
+func (ms *MemoryPictureStore) Get(hash string) ([]byte, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	data, ok := ms.blobs[hash]
+	if !ok {
+		return nil, ErrPictureNotFound
+	}
+	return data, nil
+}
+
+// DirPictureStore хранит данные изображений в локальном каталоге в файлах, именуемых
+// хешем содержимого ("ab/abcdef...").
+type DirPictureStore struct {
+	Dir string
+}
+
+// NewDirPictureStore создает объект DirPictureStore и каталог хранилища.
+func NewDirPictureStore(dir string) (*DirPictureStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DirPictureStore{Dir: dir}, nil
+}
+
+func (ds *DirPictureStore) path(hash string) (string, error) {
+	if len(hash) != sha256.Size*2 {
+		return "", fmt.Errorf("picture: wrong hash %q", hash)
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", fmt.Errorf("picture: wrong hash %q", hash)
+	}
+	return filepath.Join(ds.Dir, hash[:2], hash), nil
+}
+
+// Put сохраняет данные изображения, если они еще не сохранены, и возвращает их хеш.
+func (ds *DirPictureStore) Put(data []byte) (string, error) {
+	hash := PictureHash(data)
+	path, err := ds.path(hash)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return hash, nil
+}
+
+// Get возвращает данные изображения по хешу.
+func (ds *DirPictureStore) Get(hash string) ([]byte, error) {
+	path, err := ds.path(hash)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrPictureNotFound
+	}
+	return data, err
+}
+
+// Detach переносит данные изображения в хранилище, оставляя в объекте только хеш.
+func (pia *PictureInAudio) Detach(store PictureStore) error {
+	if len(pia.Data) == 0 {
+		return nil
+	}
+	hash, err := store.Put(pia.Data)
+	if err != nil {
+		return err
+	}
+	pia.Hash = hash
+	pia.Data = nil
+	return nil
+}
+
+// Rehydrate восстанавливает данные изображения из хранилища по хешу и проверяет их
+// целостность.
+func (pia *PictureInAudio) Rehydrate(store PictureStore) error {
+	if len(pia.Data) > 0 || pia.Hash == "" {
+		return nil
+	}
+	data, err := store.Get(pia.Hash)
+	if err != nil {
+		return err
+	}
+	if PictureHash(data) != pia.Hash {
+		return fmt.Errorf("picture %s: hash mismatch", pia.Hash)
+	}
+	pia.Data = data
+	return nil
+}
+
+// releasePictures возвращает изображения релиза и оригинального релиза.
+func releasePictures(r *Release) []*PictureInAudio {
+	var ret []*PictureInAudio
+	if r == nil {
+		return nil
+	}
+	if r.ReleaseStub != nil {
+		ret = append(ret, r.Pictures...)
+	}
+	if r.Original != nil {
+		ret = append(ret, r.Original.Pictures...)
+	}
+	return ret
+}
+
+func detachPictures(store PictureStore, pictures []*PictureInAudio) error {
+	for _, pia := range pictures {
+		if err := pia.Detach(store); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rehydratePictures(store PictureStore, pictures []*PictureInAudio) error {
+	for _, pia := range pictures {
+		if err := pia.Rehydrate(store); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DedupPictures удаляет повторяющиеся изображения релиза.
+func (r *Release) DedupPictures() {
+	if r.ReleaseStub != nil {
+		r.Pictures = DedupPictures(r.Pictures)
+	}
+	if r.Original != nil {
+		r.Original.Pictures = DedupPictures(r.Original.Pictures)
+	}
+}
+
+// DetachPictures переносит данные изображений в хранилище, оставляя в JSON только хеши.
+func (as *Assumption) DetachPictures(store PictureStore) error {
+	if err := detachPictures(store, as.Pictures); err != nil {
+		return err
+	}
+	return detachPictures(store, releasePictures(as.Release))
+}
+
+// RehydratePictures восстанавливает данные изображений из хранилища.
+func (as *Assumption) RehydratePictures(store PictureStore) error {
+	if err := rehydratePictures(store, as.Pictures); err != nil {
+		return err
+	}
+	return rehydratePictures(store, releasePictures(as.Release))
+}
+
+// DetachPictures переносит данные изображений в хранилище, оставляя в JSON только хеши.
+func (ss *SuggestionSet) DetachPictures(store PictureStore) error {
+	if err := detachPictures(store, ss.Pictures); err != nil {
+		return err
+	}
+	for _, s := range ss.Suggestions {
+		if err := detachPictures(store, releasePictures(s.Release)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RehydratePictures восстанавливает данные изображений из хранилища.
+func (ss *SuggestionSet) RehydratePictures(store PictureStore) error {
+	if err := rehydratePictures(store, ss.Pictures); err != nil {
+		return err
+	}
+	for _, s := range ss.Suggestions {
+		if err := rehydratePictures(store, releasePictures(s.Release)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pictureKeys возвращает ключи изображений перечня (см. pictureKey).
+func pictureKeys(pictures []*PictureInAudio) map[string]bool {
+	ret := map[string]bool{}
+	for _, pia := range pictures {
+		ret[pictureKey(pia)] = true
+	}
+	return ret
+}
+
+// hoistPictures переносит изображения с данными в общий перечень pool без повторов
+// и возвращает перечень, где перенесенные изображения заменены ссылками на них по хешу.
+// seen содержит ключи изображений, уже присутствующих в pool.
+func hoistPictures(
+	pool *[]*PictureInAudio, pictures []*PictureInAudio, seen map[string]bool) []*PictureInAudio {
+	var ret []*PictureInAudio
+	for _, pia := range pictures {
+		if len(pia.Data) == 0 {
+			ret = append(ret, pia)
+			continue
+		}
+		pia.ComputeHash()
+		if key := pictureKey(pia); !seen[key] {
+			seen[key] = true
+			*pool = append(*pool, pia)
+		}
+		ret = append(ret, &PictureInAudio{
+			PictureMetadata: pia.PictureMetadata,
+			PictType:        pia.PictType,
+			Notes:           pia.Notes,
+			CoverURL:        pia.CoverURL,
+			Hash:            pia.Hash,
+			Derived:         pia.Derived,
+			PHash:           pia.PHash})
+	}
+	return ret
+}
@@ -0,0 +1,138 @@
+package metadata
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPictureHash(t *testing.T) {
+	pia := &PictureInAudio{Data: []byte("JPEG")}
+	hash := pia.ComputeHash()
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, PictureHash([]byte("JPEG")))
+	assert.True(t, pia.SamePicture(&PictureInAudio{Hash: hash}))
+	assert.False(t, pia.SamePicture(&PictureInAudio{Hash: hash, PictType: PictTypeCoverBack}))
+	assert.False(t, pia.SamePicture(&PictureInAudio{Data: []byte("PNG")}))
+
+	other := &PictureInAudio{Data: []byte("JPEG")}
+	assert.True(t, other.SamePicture(pia))
+	assert.Empty(t, other.Hash)
+	assert.False(t, (&PictureInAudio{}).SamePicture(&PictureInAudio{}))
+}
+
+func TestDedupPictures(t *testing.T) {
+	pictures := []*PictureInAudio{
+		{PictType: PictTypeCoverFront, Data: []byte("JPEG")},
+		{PictType: PictTypeCoverFront, Data: []byte("JPEG")},
+		{PictType: PictTypeCoverBack, Data: []byte("JPEG")},
+		{PictType: PictTypeCoverFront, CoverURL: "https://example.com/1.jpg"},
+		{PictType: PictTypeCoverFront, CoverURL: "https://example.com/1.jpg"},
+	}
+	res := DedupPictures(pictures)
+	require.Len(t, res, 3)
+	assert.Same(t, pictures[0], res[0])
+	assert.Same(t, pictures[2], res[1])
+	assert.Same(t, pictures[3], res[2])
+}
+
+func TestAssumptionOptimizeDedupPictures(t *testing.T) {
+	release := NewRelease()
+	for i := 0; i < 3; i++ {
+		release.Pictures = append(release.Pictures,
+			&PictureInAudio{PictType: PictTypeCoverFront, Data: []byte("JPEG")})
+	}
+	release.Pictures = append(release.Pictures,
+		&PictureInAudio{PictType: PictTypeCoverFront, CoverURL: "https://example.com/1.jpg"})
+	release.Original = NewReleaseStub()
+	release.Original.Pictures = append(release.Original.Pictures,
+		&PictureInAudio{PictType: PictTypeCoverFront, Data: []byte("JPEG")},
+		&PictureInAudio{PictType: PictTypeCoverBack, Data: []byte("JPEG")})
+	as := NewAssumption(release)
+	as.Optimize()
+	require.Len(t, as.Pictures, 2)
+	hash := as.Pictures[0].Hash
+	assert.NotEmpty(t, hash)
+	require.Len(t, as.Release.Pictures, 4)
+	for _, pia := range as.Release.Pictures[:3] {
+		assert.Empty(t, pia.Data)
+		assert.Equal(t, hash, pia.Hash)
+	}
+	assert.Equal(t, "https://example.com/1.jpg", as.Release.Pictures[3].CoverURL)
+	require.Len(t, as.Release.Original.Pictures, 2)
+	for _, pia := range as.Release.Original.Pictures {
+		assert.Empty(t, pia.Data)
+		assert.Equal(t, hash, pia.Hash)
+	}
+	assert.Equal(t, PictTypeCoverBack, as.Pictures[1].PictType)
+}
+
+func TestSuggestionSetOptimizePictures(t *testing.T) {
+	ss := NewSuggestionSet()
+	for i := 0; i < 2; i++ {
+		s := NewSuggestion()
+		s.Release.Pictures = append(s.Release.Pictures, &PictureInAudio{
+			PictType:        PictTypeCoverFront,
+			Data:            []byte("JPEG"),
+			PictureMetadata: &PictureMetadata{MimeType: "image/jpeg", Width: 500, Height: 500}})
+		ss.Suggestions = append(ss.Suggestions, s)
+	}
+	ss.Optimize()
+	require.Len(t, ss.Pictures, 1)
+	hash := ss.Pictures[0].Hash
+	for _, s := range ss.Suggestions {
+		require.Len(t, s.Release.Pictures, 1)
+		assert.Empty(t, s.Release.Pictures[0].Data)
+		assert.Equal(t, hash, s.Release.Pictures[0].Hash)
+		require.NotNil(t, s.Release.Pictures[0].PictureMetadata)
+		assert.Equal(t, uint32(500), s.Release.Pictures[0].Width)
+	}
+}
+
+func TestDirPictureStore(t *testing.T) {
+	store, err := NewDirPictureStore(filepath.Join(t.TempDir(), "pictures"))
+	require.NoError(t, err)
+	hash, err := store.Put([]byte("JPEG"))
+	require.NoError(t, err)
+	hash2, err := store.Put([]byte("JPEG"))
+	require.NoError(t, err)
+	assert.Equal(t, hash, hash2)
+	data, err := os.ReadFile(filepath.Join(store.Dir, hash[:2], hash))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("JPEG"), data)
+
+	data, err = store.Get(hash)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("JPEG"), data)
+	_, err = store.Get(PictureHash([]byte("PNG")))
+	assert.ErrorIs(t, err, ErrPictureNotFound)
+	_, err = store.Get("../../etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestPictureDetachRehydrate(t *testing.T) {
+	store := NewMemoryPictureStore()
+	release := NewRelease()
+	release.Pictures = append(release.Pictures,
+		&PictureInAudio{PictType: PictTypeCoverFront, Data: []byte("JPEG")})
+	as := NewAssumption(release)
+	as.Optimize()
+	require.NoError(t, as.DetachPictures(store))
+	data, err := json.Marshal(as)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"data"`)
+	assert.Contains(t, string(data), `"hash":"`+PictureHash([]byte("JPEG"))+`"`)
+
+	as2 := NewAssumption(nil)
+	require.NoError(t, json.Unmarshal(data, as2))
+	require.NoError(t, as2.RehydratePictures(store))
+	require.Len(t, as2.Pictures, 1)
+	assert.Equal(t, []byte("JPEG"), as2.Pictures[0].Data)
+
+	missing := &PictureInAudio{Hash: PictureHash([]byte("PNG"))}
+	assert.ErrorIs(t, missing.Rehydrate(store), ErrPictureNotFound)
+}
@@ -0,0 +1,285 @@
+package metadata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Префиксы позиций, обозначающие отдельный носитель релиза.
+var positionMediaPrefixes = map[string]int{
+	"CD":      0,
+	"DISC":    0,
+	"DISK":    0,
+	"SACD":    0,
+	"LP":      0,
+	"MC":      0,
+	"DVD":     1,
+	"BD":      1,
+	"BLU-RAY": 1,
+	"VIDEO":   1,
+}
+
+// TrackPosition описывает разобранную позицию трека.
+// Поддерживаются нотации "диск-трек" ("1-05", "2.10", "CD2-3"), стороны винила
+// ("A1", "AA", "B2.b", "A1a") и отдельные носители ("Video 1", "DVD1-2").
+// SubIndex указывает на подтрек индексного трека (например, части произведения
+// "3.a", "3.b" индексного трека "3").
+type TrackPosition struct {
+	Raw      string `json:"-"`
+	Prefix   string `json:"prefix,omitempty"`
+	Side     string `json:"side,omitempty"`
+	Disc     int    `json:"disc,omitempty"`
+	Number   int    `json:"number,omitempty"`
+	SubIndex string `json:"sub_index,omitempty"`
+}
+
+// ParsePosition разбирает строковое представление позиции трека.
+func ParsePosition(pos string) TrackPosition {
+	ret := TrackPosition{Raw: pos}
+	s := strings.TrimSpace(pos)
+	s = ret.parsePrefix(s)
+	s = ret.parseSide(s)
+	num, s := leadingNumber(s)
+	if num == -1 {
+		ret.SubIndex = strings.TrimLeft(s, ".-_ ")
+		return ret
+	}
+	ret.Number = num
+	rest := strings.TrimLeft(s, " ")
+	if len(rest) > 1 && strings.ContainsRune("-.", rune(rest[0])) {
+		next, tail := leadingNumber(strings.TrimLeft(rest[1:], " "))
+		if next != -1 && ret.Side == "" && ret.Disc == 0 {
+			ret.Disc, ret.Number = ret.Number, next
+			rest = tail
+		}
+	}
+	ret.SubIndex = strings.TrimLeft(rest, ".-_ ")
+	return ret
+}
+
+func (tp *TrackPosition) parsePrefix(s string) string {
+	upper := strings.ToUpper(s)
+	for prefix := range positionMediaPrefixes {
+		if !strings.HasPrefix(upper, prefix) {
+			continue
+		}
+		rest := s[len(prefix):]
+		if rest != "" && unicode.IsLetter(rune(rest[0])) {
+			continue
+		}
+		tp.Prefix = s[:len(prefix)]
+		rest = strings.TrimLeft(rest, " ")
+		// "CD2-3": номер носителя перед разделителем
+		if num, tail := leadingNumber(rest); num != -1 && len(tail) > 1 &&
+			strings.ContainsRune("-.", rune(tail[0])) {
+			if next, _ := leadingNumber(tail[1:]); next != -1 {
+				tp.Disc = num
+				return tail[1:]
+			}
+		}
+		return rest
+	}
+	return s
+}
+
+func (tp *TrackPosition) parseSide(s string) string {
+	var i int
+	for i < len(s) && s[i] < unicode.MaxASCII && unicode.IsUpper(rune(s[i])) {
+		i++
+	}
+	if i == 0 || i > 2 || i == 2 && s[0] != s[1] {
+		return s
+	}
+	tp.Side = s[:i]
+	return strings.TrimLeft(s[i:], ".- ")
+}
+
+func leadingNumber(s string) (int, string) {
+	var i int
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return -1, s
+	}
+	num, _ := strconv.Atoi(s[:i])
+	return num, s[i:]
+}
+
+// SideNumber возвращает порядковый номер стороны носителя ("A" - 1, "B" - 2, "C" - 3).
+// Сдвоенная сторона сингла "AA" считается второй стороной. При отсутствии стороны
+// возвращается 0.
+func (tp TrackPosition) SideNumber() int {
+	switch len(tp.Side) {
+	case 1:
+		return int(tp.Side[0]-'A') + 1
+	case 2:
+		return 2 * (int(tp.Side[0]-'A') + 1)
+	}
+	return 0
+}
+
+// DiscNumber возвращает номер диска: явно указанный в позиции, вычисленный по стороне
+// носителя (стороны A и B - первый диск, C и D - второй и т.д.) или 1 по умолчанию.
+func (tp TrackPosition) DiscNumber() int {
+	if tp.Disc > 0 {
+		return tp.Disc
+	}
+	if side := tp.SideNumber(); side > 0 {
+		return (side + 1) / 2
+	}
+	return 1
+}
+
+// IsSubTrack проверяет, является ли позиция подтреком индексного трека.
+func (tp TrackPosition) IsSubTrack() bool {
+	return tp.SubIndex != ""
+}
+
+// IndexTrack возвращает позицию индексного трека для подтрека.
+func (tp TrackPosition) IndexTrack() TrackPosition {
+	ret := tp
+	ret.SubIndex = ""
+	ret.Raw = ret.String()
+	return ret
+}
+
+// IsEmpty проверяет отсутствие значимых данных позиции.
+func (tp TrackPosition) IsEmpty() bool {
+	return tp.Prefix == "" && tp.Side == "" && tp.Disc == 0 && tp.Number == 0 &&
+		tp.SubIndex == ""
+}
+
+// String возвращает каноническое представление позиции.
+func (tp TrackPosition) String() string {
+	var sb strings.Builder
+	if tp.Prefix != "" {
+		sb.WriteString(tp.Prefix)
+		if tp.Disc == 0 {
+			sb.WriteString(" ")
+		}
+	}
+	switch {
+	case tp.Side != "":
+		sb.WriteString(tp.Side)
+		if tp.Number > 0 {
+			sb.WriteString(strconv.Itoa(tp.Number))
+		}
+	case tp.Disc > 0:
+		fmt.Fprintf(&sb, "%d-%02d", tp.Disc, tp.Number)
+	case tp.Number > 0:
+		fmt.Fprintf(&sb, "%02d", tp.Number)
+	}
+	if tp.SubIndex != "" {
+		sb.WriteString("." + tp.SubIndex)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// DiscNotation возвращает позицию в нотации "диск-трек" без учета стороны носителя.
+// Номер трека на стороне при этом не пересчитывается (см. ToDiscNotation).
+func (tp TrackPosition) DiscNotation() string {
+	ret := fmt.Sprintf("%d-%02d", tp.DiscNumber(), tp.Number)
+	if tp.SubIndex != "" {
+		ret += "." + tp.SubIndex
+	}
+	return ret
+}
+
+// Compare сравнивает позиции для сортировки и возвращает -1, 0 или 1.
+// Порядок: аудио-носители перед видео, номер диска, сторона, номер трека, подтрек.
+func (tp TrackPosition) Compare(other TrackPosition) int {
+	keys := [][2]int{
+		{positionMediaPrefixes[strings.ToUpper(tp.Prefix)],
+			positionMediaPrefixes[strings.ToUpper(other.Prefix)]},
+		{tp.DiscNumber(), other.DiscNumber()},
+		{tp.SideNumber(), other.SideNumber()},
+		{tp.Number, other.Number},
+	}
+	for _, k := range keys {
+		switch {
+		case k[0] < k[1]:
+			return -1
+		case k[0] > k[1]:
+			return 1
+		}
+	}
+	return compareSubIndex(tp.SubIndex, other.SubIndex)
+}
+
+func compareSubIndex(s1, s2 string) int {
+	n1, err1 := strconv.Atoi(s1)
+	n2, err2 := strconv.Atoi(s2)
+	if err1 == nil && err2 == nil {
+		s1, s2 = fmt.Sprintf("%09d", n1), fmt.Sprintf("%09d", n2)
+	}
+	return strings.Compare(strings.ToLower(s1), strings.ToLower(s2))
+}
+
+// Less проверяет, предшествует ли позиция другой позиции.
+func (tp TrackPosition) Less(other TrackPosition) bool {
+	return tp.Compare(other) < 0
+}
+
+// Equal проверяет совпадение позиций.
+func (tp TrackPosition) Equal(other TrackPosition) bool {
+	return !tp.IsEmpty() && tp.Compare(other) == 0
+}
+
+// ToDiscNotation преобразует перечень позиций сторон винила ("A1", "A2", "B1", "C1") в
+// нотацию "диск-трек" ("1-01", "1-02", "1-03", "2-01") со сквозной нумерацией треков диска.
+// Позиции должны быть упорядочены.
+func ToDiscNotation(positions []string) []string {
+	ret := make([]string, len(positions))
+	counters := map[int]int{}
+	for i, pos := range positions {
+		tp := ParsePosition(pos)
+		disc := tp.DiscNumber()
+		if tp.SubIndex == "" || counters[disc] == 0 {
+			counters[disc]++
+		}
+		tp.Disc, tp.Side, tp.Prefix, tp.Number = disc, "", "", counters[disc]
+		ret[i] = tp.DiscNotation()
+	}
+	return ret
+}
+
+// ToSideNotation преобразует перечень позиций в нотации "диск-трек" в нотацию сторон
+// винила. sideLengths задает количество треков на каждой стороне по порядку (A, B, C, ...).
+// Если sideLengths не задан, треки каждого диска делятся между двумя сторонами поровну
+// (при нечетном количестве лишний трек относится к первой стороне).
+// Позиции должны быть упорядочены.
+func ToSideNotation(positions []string, sideLengths []int) []string {
+	parsed := make([]TrackPosition, len(positions))
+	discLengths := map[int]int{}
+	for i, pos := range positions {
+		parsed[i] = ParsePosition(pos)
+		if !parsed[i].IsSubTrack() {
+			discLengths[parsed[i].DiscNumber()]++
+		}
+	}
+	ret := make([]string, len(positions))
+	side, number, lastDisc := 0, 0, 0
+	for i, tp := range parsed {
+		disc := tp.DiscNumber()
+		if disc != lastDisc {
+			side, number, lastDisc = 2*(disc-1), 0, disc
+		}
+		if !tp.IsSubTrack() {
+			number++
+		}
+		limit := (discLengths[disc] + 1) / 2
+		if side < len(sideLengths) {
+			limit = sideLengths[side]
+		}
+		if number > limit && side%2 == 0 {
+			side++
+			number = 1
+		}
+		tp.Prefix, tp.Disc, tp.Side, tp.Number = "", 0, string(rune('A'+side)), number
+		ret[i] = tp.String()
+	}
+	return ret
+}
@@ -0,0 +1,77 @@
+package metadata
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePosition(t *testing.T) {
+	results := map[string]TrackPosition{
+		"1-05":    {Disc: 1, Number: 5},
+		"3 - 1":   {Disc: 3, Number: 1},
+		"CD2-3":   {Prefix: "CD", Disc: 2, Number: 3},
+		"A1":      {Side: "A", Number: 1},
+		"AA":      {Side: "AA"},
+		"B2.b":    {Side: "B", Number: 2, SubIndex: "b"},
+		"A1a":     {Side: "A", Number: 1, SubIndex: "a"},
+		"Video 1": {Prefix: "Video", Number: 1},
+		"12":      {Number: 12},
+		"3.a":     {Number: 3, SubIndex: "a"},
+		"":        {},
+	}
+	for k, v := range results {
+		v.Raw = k
+		assert.Equal(t, v, ParsePosition(k), k)
+	}
+}
+
+func TestTrackPositionDiscNumber(t *testing.T) {
+	assert.Equal(t, 1, ParsePosition("AA").DiscNumber())
+	assert.Equal(t, 2, ParsePosition("C1").DiscNumber())
+	assert.Equal(t, 2, ParsePosition("CD2-3").DiscNumber())
+	assert.Equal(t, 1, ParsePosition("Video 1").DiscNumber())
+}
+
+func TestTrackPositionString(t *testing.T) {
+	assert.Equal(t, "1-05", ParsePosition("1.5").String())
+	assert.Equal(t, "CD2-03", ParsePosition("CD2-3").String())
+	assert.Equal(t, "B2.b", ParsePosition("B2b").String())
+	assert.Equal(t, "Video 01", ParsePosition("Video 1").String())
+	assert.Equal(t, "B2", ParsePosition("B2.b").IndexTrack().String())
+	assert.True(t, ParsePosition("B2.b").IsSubTrack())
+}
+
+func TestTrackPositionOrdering(t *testing.T) {
+	sortPositions := func(positions []string) []string {
+		sort.Slice(positions, func(i, j int) bool {
+			return ParsePosition(positions[i]).Less(ParsePosition(positions[j]))
+		})
+		return positions
+	}
+	assert.Equal(t,
+		[]string{"A2", "A10", "B1", "B2", "B2.a", "B2.b", "C1"},
+		sortPositions([]string{"B2.b", "C1", "A2", "B2.a", "A10", "B1", "B2"}))
+	assert.Equal(t, []string{"A", "AA"}, sortPositions([]string{"AA", "A"}))
+	assert.Equal(t,
+		[]string{"1-02", "1-10", "CD2-1", "Video 1"},
+		sortPositions([]string{"Video 1", "CD2-1", "1-10", "1-02"}))
+	assert.True(t, ParsePosition("1-05").Equal(ParsePosition("1.5")))
+	assert.False(t, ParsePosition("").Equal(ParsePosition("")))
+}
+
+func TestToDiscNotation(t *testing.T) {
+	assert.Equal(t,
+		[]string{"1-01", "1-02", "1-03", "1-03.a", "2-01", "2-02"},
+		ToDiscNotation([]string{"A1", "A2", "B1", "B1.a", "C1", "D1"}))
+}
+
+func TestToSideNotation(t *testing.T) {
+	assert.Equal(t,
+		[]string{"A1", "A2", "B1", "C1", "D1"},
+		ToSideNotation([]string{"1-01", "1-02", "1-03", "2-01", "2-02"}, nil))
+	assert.Equal(t,
+		[]string{"A1", "B1", "B2"},
+		ToSideNotation([]string{"1", "2", "3"}, []int{1, 2}))
+}
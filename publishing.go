@@ -27,7 +27,18 @@ func (pid PublishingID) String() string {
 	if pid == PublishingBarcode {
 		return "barcode"
 	}
-	return ""
+	return registeredIDKey(EntityPublishing, uint8(pid))
+}
+
+// MarshalText позволяет использовать PublishingID в качестве ключа словаря JSON.
+func (pid PublishingID) MarshalText() ([]byte, error) {
+	return []byte(pid.String()), nil
+}
+
+// UnmarshalText получает PublishingID из ключа словаря JSON (см. parseIDKey).
+func (pid *PublishingID) UnmarshalText(b []byte) error {
+	*pid = PublishingID(parseIDKey(EntityPublishing, string(b)))
+	return nil
 }
 
 // MarshalJSON ..
@@ -57,7 +68,18 @@ func (lid LabelID) String() string {
 	case MusicbrainzLabelID:
 		return "musicbrainz_label_id"
 	}
-	return ""
+	return registeredIDKey(EntityLabel, uint8(lid))
+}
+
+// MarshalText позволяет использовать LabelID в качестве ключа словаря JSON.
+func (lid LabelID) MarshalText() ([]byte, error) {
+	return []byte(lid.String()), nil
+}
+
+// UnmarshalText получает LabelID из ключа словаря JSON (см. parseIDKey).
+func (lid *LabelID) UnmarshalText(b []byte) error {
+	*lid = LabelID(parseIDKey(EntityLabel, string(b)))
+	return nil
 }
 
 // LabelIDs представляет словарь идентификаторов лейблов во внешних БД.
@@ -80,7 +102,7 @@ func (lbl *LabelIDs) UnmarshalJSON(b []byte) error {
 	}
 	*lbl = make(LabelIDs, len(x))
 	for k, v := range x {
-		(*lbl)[StrToLabelID[k]] = v
+		(*lbl)[LabelID(LookupID(EntityLabel, k))] = v
 	}
 	return nil
 }
@@ -102,16 +124,18 @@ func (pids *PubIDs) UnmarshalJSON(b []byte) error {
 	}
 	*pids = make(PubIDs, len(x))
 	for k, v := range x {
-		(*pids)[StrToPublishingID[k]] = v
+		(*pids)[PublishingID(LookupID(EntityPublishing, k))] = v
 	}
 	return nil
 }
 
 // Label содержит информацию о лейбле и номере издания в каталоле
+// Parent указывает материнский лейбл, импринтом которого является лейбл.
 type Label struct {
-	Label string             `json:"label,omitempty"`
-	Catno string             `json:"catno,omitempty"`
-	IDs   map[LabelID]string `json:"ids,omitempty"`
+	Label  string             `json:"label,omitempty"`
+	Catno  string             `json:"catno,omitempty"`
+	Parent string             `json:"parent,omitempty"`
+	IDs    map[LabelID]string `json:"ids,omitempty"`
 }
 
 // NewLabel создает объект Label.
@@ -119,16 +143,44 @@ func NewLabel(lbl, catno string) *Label {
 	return &Label{Label: lbl, Catno: catno, IDs: map[LabelID]string{}}
 }
 
+// partialLabelMatch ограничивает оценку сходства лейблов, совпадение которых установлено
+// не полностью: номер в каталоге известен не для обоих лейблов или совпадает при разных
+// наименованиях.
+const partialLabelMatch = 0.9
+
 // Compare сравнивает 2 лейбла наименованию самого лейбла и по номеру в каталоге.
+// Полное совпадение возможно только при совпадении наименований и нормализованных номеров
+// в каталоге. Если номер известен только для одного из лейблов, сравниваются только
+// наименования с понижающим коэффициентом partialLabelMatch.
 func (lbl *Label) Compare(other *Label) float64 {
-	return stringutils.JaroWinklerDistance(lbl.Label, other.Label) *
-		stringutils.JaroWinklerDistance(lbl.Catno, other.Catno)
+	catno, otherCatno := CatnoKey(lbl.Catno), CatnoKey(other.Catno)
+	names := labelNameSimilarity(lbl.Label, other.Label)
+	switch {
+	case catno != "" && catno == otherCatno:
+		if names == 1 {
+			return 1
+		}
+		return partialLabelMatch
+	case catno == "" || otherCatno == "":
+		return partialLabelMatch * names
+	}
+	return names * stringutils.JaroWinklerDistance(catno, otherCatno)
+}
+
+// Normalize нормализует наименование лейбла и номер в каталоге.
+func (lbl *Label) Normalize() {
+	lbl.Label = NormalizeLabelName(lbl.Label)
+	lbl.Catno = NormalizeCatno(lbl.Catno)
 }
 
 // Publishing describes trade label of the release.
+// Companies содержит прочие компании, участвовавшие в издании (дистрибьюторы,
+// правообладатели, изготовители), а Notices - уведомления об авторских правах.
 type Publishing struct {
-	Labels []*Label                `json:"labels,omitempty"`
-	IDs    map[PublishingID]string `json:"ids,omitempty"`
+	Labels    []*Label                `json:"labels,omitempty"`
+	Companies []*Company              `json:"companies,omitempty"`
+	Notices   []*Notice               `json:"notices,omitempty"`
+	IDs       map[PublishingID]string `json:"ids,omitempty"`
 }
 
 // NewPublishing creates a new copy of ReleaseLabel object.
@@ -142,6 +194,8 @@ func (pub *Publishing) AddLabel(lbl *Label) {
 }
 
 // Compare a ReleaseLabel object with other one.
+// Штрих-коды, если они известны и проходят проверку для обоих изданий, сравниваются точно
+// и являются решающими. Штрих-коды с ошибками при сравнении не учитываются.
 func (pub *Publishing) Compare(other *Publishing) float64 {
 	var res, max float64
 	for _, lbl := range pub.Labels {
@@ -152,14 +206,41 @@ func (pub *Publishing) Compare(other *Publishing) float64 {
 			}
 		}
 	}
-	if pub.IDs[PublishingBarcode] != "" && other.IDs[PublishingBarcode] != "" {
-		max *= stringutils.JaroWinklerDistance(
-			pub.IDs[PublishingBarcode], other.IDs[PublishingBarcode])
+	bc, err := pub.Barcode()
+	otherBc, otherErr := other.Barcode()
+	if bc != nil && otherBc != nil && err == nil && otherErr == nil {
+		if bc.Equal(otherBc) {
+			return 1
+		}
+		return 0
 	}
 	return max
 }
 
+// SetBarcode проверяет и сохраняет штрих-код издания в виде строки цифр.
+func (pub *Publishing) SetBarcode(barcode string) error {
+	digits, err := NormalizeBarcode(barcode)
+	if err != nil {
+		return err
+	}
+	if pub.IDs == nil {
+		pub.IDs = map[PublishingID]string{}
+	}
+	pub.IDs[PublishingBarcode] = digits
+	return nil
+}
+
+// Barcode возвращает разобранный штрих-код издания или ошибку его проверки.
+// При отсутствии штрих-кода возвращается nil без ошибки.
+func (pub *Publishing) Barcode() (*Barcode, error) {
+	if pub.IDs[PublishingBarcode] == "" {
+		return nil, nil
+	}
+	return ParseBarcode(pub.IDs[PublishingBarcode])
+}
+
 // IsEmpty проверяет наличие в объекте значимой информации.
 func (pub *Publishing) IsEmpty() bool {
-	return len(pub.IDs) == 0 && len(pub.Labels) == 0
+	return len(pub.IDs) == 0 && len(pub.Labels) == 0 && len(pub.Companies) == 0 &&
+		len(pub.Notices) == 0
 }
@@ -2,13 +2,16 @@ package metadata
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 )
 
 // RecordingID тип для перечисления идентификаторов записи трека во внешних БД.
 type RecordingID uint8
 
 // Допустимые значения идентификаторов записи во внешних БД.
+// Коды ISRC хранятся в Record.ISRCs, ключ ISRC сохранен для чтения прежнего формата JSON.
 const (
 	MusicbrainzRecordingID RecordingID = iota + 1
 	ISRC
@@ -34,7 +37,7 @@ func (rids *RecordingIDs) UnmarshalJSON(b []byte) error {
 	}
 	*rids = make(RecordingIDs, len(x))
 	for k, v := range x {
-		(*rids)[StrToRecordingID[k]] = v
+		(*rids)[RecordingID(LookupID(EntityRecording, k))] = v
 	}
 	return nil
 }
@@ -52,25 +55,31 @@ func (rid RecordingID) String() string {
 	case ISRC:
 		return "isrc"
 	}
-	return ""
+	return registeredIDKey(EntityRecording, uint8(rid))
 }
 
-// RecordSession описывает общие свойства сессии записи.
-type RecordSession struct {
-	Place string `json:"place,omitempty"`
-	Time  string `json:"time,omitempty"`
-	Notes string `json:"notes,omitempty"`
+// MarshalText позволяет использовать RecordingID в качестве ключа словаря JSON.
+func (rid RecordingID) MarshalText() ([]byte, error) {
+	return []byte(rid.String()), nil
+}
+
+// UnmarshalText получает RecordingID из ключа словаря JSON (см. parseIDKey).
+func (rid *RecordingID) UnmarshalText(b []byte) error {
+	*rid = RecordingID(parseIDKey(EntityRecording, string(b)))
+	return nil
 }
 
 // Record содержит сведения о записи композиции.
 type Record struct {
-	Duration   int32                  `json:"duration,omitempty"`
-	Actors     ActorsIDs              `json:"actors,omitempty"`
-	ActorRoles ActorRoles             `json:"actor_roles,omitempty"`
-	Moods      Moods                  `json:"moods,omitempty"`
-	Genres     []string               `json:"genres,omitempty"`
-	IDs        map[RecordingID]string `json:"ids,omitempty"`
-	Notes      string                 `json:"notes,omitempty"`
+	Duration   int32            `json:"duration,omitempty"`
+	Actors     ActorsIDs        `json:"actors,omitempty"`
+	ActorRoles ActorRoles       `json:"actor_roles,omitempty"`
+	Moods      Moods            `json:"moods,omitempty"`
+	Genres     []string         `json:"genres,omitempty"`
+	IDs        RecordingIDs     `json:"ids,omitempty"`
+	ISRCs      []string         `json:"isrcs,omitempty"`
+	Sessions   []*RecordSession `json:"sessions,omitempty"`
+	Notes      string           `json:"notes,omitempty"`
 }
 
 // NewRecord создает новый объект Record.
@@ -78,7 +87,7 @@ func NewRecord() *Record {
 	return &Record{
 		Actors:     ActorsIDs{},
 		ActorRoles: ActorRoles{},
-		IDs:        map[RecordingID]string{},
+		IDs:        RecordingIDs{},
 	}
 }
 
@@ -100,6 +109,74 @@ func (p *Record) Clean() {
 	if len(p.IDs) == 0 {
 		p.IDs = nil
 	}
+	if len(p.ISRCs) == 0 {
+		p.ISRCs = nil
+	}
+	p.cleanSessions()
+}
+
+// UnmarshalJSON получает сведения о записи из значения JSON.
+// Код ISRC из словаря идентификаторов прежнего формата переносится в ISRCs, а не прошедший
+// проверку код остается в словаре идентификаторов без изменений.
+func (p *Record) UnmarshalJSON(b []byte) error {
+	type record Record
+	if err := json.Unmarshal(b, (*record)(p)); err != nil {
+		return err
+	}
+	if isrc, ok := p.IDs[ISRC]; ok && p.AddISRC(isrc) == nil {
+		delete(p.IDs, ISRC)
+	}
+	return nil
+}
+
+// NormalizeISRC проверяет код ISRC (ISO 3901) и возвращает его в виде 12 символов
+// в верхнем регистре без разделителей.
+func NormalizeISRC(isrc string) (string, error) {
+	isrc = strings.ToUpper(strings.Join(strings.Fields(isrc), ""))
+	isrc = strings.TrimPrefix(isrc, "ISRC")
+	isrc = strings.ReplaceAll(isrc, "-", "")
+	if len(isrc) != 12 {
+		return "", fmt.Errorf("ISRC %q: wrong length", isrc)
+	}
+	for i, r := range isrc {
+		var ok bool
+		switch {
+		case i < 2:
+			ok = r >= 'A' && r <= 'Z'
+		case i < 5:
+			ok = r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+		default:
+			ok = r >= '0' && r <= '9'
+		}
+		if !ok {
+			return "", fmt.Errorf("ISRC %q: wrong character %q", isrc, r)
+		}
+	}
+	return isrc, nil
+}
+
+// FormatISRC возвращает код ISRC в каноническом виде "GB-AYE-69-00531".
+func FormatISRC(isrc string) string {
+	isrc, err := NormalizeISRC(isrc)
+	if err != nil {
+		return ""
+	}
+	return strings.Join([]string{isrc[:2], isrc[2:5], isrc[5:7], isrc[7:]}, "-")
+}
+
+// AddISRC проверяет и добавляет код ISRC записи. Повторы игнорируются.
+func (p *Record) AddISRC(isrc string) error {
+	isrc, err := NormalizeISRC(isrc)
+	if err != nil {
+		return err
+	}
+	for _, v := range p.ISRCs {
+		if v == isrc {
+			return nil
+		}
+	}
+	p.ISRCs = append(p.ISRCs, isrc)
+	return nil
 }
 
 // AddRole добавляет роль для актора записи.
@@ -44,3 +44,42 @@ func TestRecordingIDsUnmarshal(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, m, ISRC)
 }
+
+func TestNormalizeISRC(t *testing.T) {
+	for _, s := range []string{"GB-AYE-69-00531", "gbaye6900531", "ISRC GB AYE 69 00531"} {
+		isrc, err := NormalizeISRC(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, "GBAYE6900531", isrc, s)
+	}
+	assert.Equal(t, "GB-AYE-69-00531", FormatISRC("GBAYE6900531"))
+	for _, s := range []string{"", "GBAYE690053", "1BAYE6900531", "GBAYE69A0531"} {
+		_, err := NormalizeISRC(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRecordAddISRC(t *testing.T) {
+	r := NewRecord()
+	require.NoError(t, r.AddISRC("GB-AYE-69-00531"))
+	require.NoError(t, r.AddISRC("gbaye6900531"))
+	require.NoError(t, r.AddISRC("USCA29800012"))
+	assert.Error(t, r.AddISRC("bad"))
+	assert.Equal(t, []string{"GBAYE6900531", "USCA29800012"}, r.ISRCs)
+}
+
+func TestRecordUnmarshalLegacyISRC(t *testing.T) {
+	var r Record
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"ids":{"isrc":"GB-AYE-69-00531","musicbrainz_recording_id":"abc"}}`), &r))
+	assert.Equal(t, []string{"GBAYE6900531"}, r.ISRCs)
+	assert.Equal(t, RecordingIDs{MusicbrainzRecordingID: "abc"}, r.IDs)
+}
+
+func TestReleaseUnmarshalInvalidRecordISRC(t *testing.T) {
+	r := NewRelease()
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"title":"x","tracks":[{"record":{"ids":{"isrc":"bad"}}}]}`), r))
+	require.Len(t, r.Tracks, 1)
+	assert.Empty(t, r.Tracks[0].Record.ISRCs)
+	assert.Equal(t, "bad", r.Tracks[0].Record.IDs[ISRC])
+}
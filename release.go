@@ -2,7 +2,9 @@ package metadata
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 
 	collection "github.com/ytsiuryn/go-collection"
@@ -43,7 +45,18 @@ func (rid ReleaseID) String() string {
 	case Asin:
 		return "asin"
 	}
-	return ""
+	return registeredIDKey(EntityRelease, uint8(rid))
+}
+
+// MarshalText позволяет использовать ReleaseID в качестве ключа словаря JSON.
+func (rid ReleaseID) MarshalText() ([]byte, error) {
+	return []byte(rid.String()), nil
+}
+
+// UnmarshalText получает ReleaseID из ключа словаря JSON (см. parseIDKey).
+func (rid *ReleaseID) UnmarshalText(b []byte) error {
+	*rid = ReleaseID(parseIDKey(EntityRelease, string(b)))
+	return nil
 }
 
 // StrToReleaseID ..
@@ -78,7 +91,7 @@ func (rids *ReleaseIDs) UnmarshalJSON(b []byte) error {
 	}
 	*rids = make(ReleaseIDs, len(x))
 	for k, v := range x {
-		(*rids)[StrToReleaseID[k]] = v
+		(*rids)[ReleaseID(LookupID(EntityRelease, k))] = v
 	}
 	return nil
 }
@@ -91,26 +104,30 @@ type Release struct {
 
 // ReleaseStub отражает коммерческую суть продажи альбома.
 type ReleaseStub struct {
-	Title         string      `json:"title"`
-	TotalDiscs    int         `json:"total_discs,omitempty"`
-	Discs         []*Disc     `json:"discs,omitempty"`
-	TotalTracks   int         `json:"total_tracks,omitempty"`
-	Tracks        []*Track    `json:"tracks,omitempty"`
-	Publishing    *Publishing `json:"publishing,omitempty"`
-	Country       string      `json:"country,omitempty"`
-	Year          int         `json:"year,omitempty"`
-	Notes         string      `json:"notes,omitempty"`
-	ReleaseStatus `json:"release_status,omitempty"`
-	ReleaseType   `json:"release_type,omitempty"`
-	ReleaseRepeat `json:"release_repeat,omitempty"`
-	ReleaseRemake `json:"release_remake,omitempty"`
-	ReleaseOrigin `json:"release_origin,omitempty"`
-	Actors        ActorsIDs            `json:"actors,omitempty"`
-	ActorRoles    ActorRoles           `json:"actors_roles,omitempty"`
-	IDs           map[ReleaseID]string `json:"ids,omitempty"`
-	Pictures      []*PictureInAudio    `json:"pictures,omitempty"`
-	Unprocessed   collection.StrMap    `json:"unprocessed,omitempty"` // for ext view mode
-	wg            sync.WaitGroup
+	Title          string          `json:"title"`
+	Edition        string          `json:"edition,omitempty"`
+	TotalDiscs     int             `json:"total_discs,omitempty"`
+	Discs          []*Disc         `json:"discs,omitempty"`
+	TotalTracks    int             `json:"total_tracks,omitempty"`
+	Tracks         []*Track        `json:"tracks,omitempty"`
+	Publishing     *Publishing     `json:"publishing,omitempty"`
+	Country        string          `json:"country,omitempty"`
+	Year           int             `json:"year,omitempty"`
+	Events         []*ReleaseEvent `json:"release_events,omitempty"`
+	Notes          string          `json:"notes,omitempty"`
+	ReleaseStatus  `json:"release_status,omitempty"`
+	ReleaseType    `json:"release_type,omitempty"`
+	SecondaryTypes []ReleaseType `json:"release_secondary_types,omitempty"`
+	ReleaseRepeat  `json:"release_repeat,omitempty"`
+	ReleaseRemake  `json:"release_remake,omitempty"`
+	ReleaseOrigin  `json:"release_origin,omitempty"`
+	Actors         ActorsIDs            `json:"actors,omitempty"`
+	ActorsInfo     ActorsInfo           `json:"actors_info,omitempty"`
+	ActorRoles     ActorRoles           `json:"actors_roles,omitempty"`
+	IDs            map[ReleaseID]string `json:"ids,omitempty"`
+	Pictures       []*PictureInAudio    `json:"pictures,omitempty"`
+	Unprocessed    collection.StrMap    `json:"unprocessed,omitempty"` // for ext view mode
+	wg             sync.WaitGroup
 }
 
 // NewRelease construct a new release object.
@@ -135,8 +152,8 @@ func NewReleaseStub() *ReleaseStub {
 // IsEmpty проверяет возможность сбросить ссылку на объект в nil, если все его поля
 // установлены в нулевое значение.
 func (stub *ReleaseStub) IsEmpty() bool {
-	return reflect.DeepEqual(*stub, ReleaseStub{}) ||
-		reflect.DeepEqual(*stub, *NewReleaseStub())
+	return reflect.DeepEqual(stub, &ReleaseStub{}) ||
+		reflect.DeepEqual(stub, NewReleaseStub())
 }
 
 // Clean ..
@@ -147,6 +164,9 @@ func (stub *ReleaseStub) Clean() {
 			stub.Actors = nil
 		}
 	}
+	if stub.ActorsInfo.IsEmpty() {
+		stub.ActorsInfo = nil
+	}
 	stub.ActorRoles.Clean()
 	if stub.ActorRoles.IsEmpty() {
 		stub.ActorRoles = nil
@@ -170,14 +190,9 @@ func (stub *ReleaseStub) Clean() {
 	}
 }
 
-// Cover возвращает объект PictureInAudio, если он описывает обложку альбома, или nil.
+// Cover возвращает лучшую по правилам DefaultCoverRules лицевую обложку альбома или nil.
 func (r *Release) Cover() *PictureInAudio {
-	for _, pia := range r.Pictures {
-		if pia.PictType == PictTypeCoverFront {
-			return pia
-		}
-	}
-	return nil
+	return DefaultCoverRules.SelectCover(r.Pictures)
 }
 
 // TrackByPosition возвращает объект трека по его позиции.
@@ -194,23 +209,46 @@ func (r *Release) TrackByPosition(pos string) *Track {
 // Если диск с указанным номером не существует, он добавляется в колекцию в позицию,
 // соответствующую его номеру с заполнением "пробелов".
 func (r *Release) Disc(num int) *Disc {
-	lenDiff := num - len(r.Discs)
-	for i := 0; i < lenDiff; i++ {
-		r.Discs = append(r.Discs, NewDisc(len(r.Discs)+i+1))
+	var ret *Disc
+	exists := map[int]bool{}
+	for _, d := range r.Discs {
+		exists[d.Number] = true
+		if d.Number == num {
+			ret = d
+		}
+	}
+	if ret != nil {
+		return ret
+	}
+	for n := 1; n <= num; n++ {
+		if !exists[n] {
+			r.Discs = append(r.Discs, NewDisc(n))
+		}
 	}
-	return r.Discs[num-1]
+	sort.SliceStable(r.Discs, func(i, j int) bool {
+		return r.Discs[i].Number < r.Discs[j].Number
+	})
+	for _, d := range r.Discs {
+		if d.Number == num {
+			ret = d
+		}
+	}
+	return ret
 }
 
 // --- COMPARE METHODS ---
 
 // Compare compare two albums by important metadata.
-// Если номера каталогов изданий совпадают, объекты считаются идентичными досрочно.
+// Если совпадают штрих-коды или лейблы с номерами в каталоге, объекты считаются
+// идентичными досрочно.
+// Названия сравниваются без сведений о версии.
 func (r *Release) Compare(other *Release) float64 {
 	labsR, labsW := r.pubCompare(other)
 	if labsR == 1. {
 		return 1.
 	}
-	titleR, titleW := stringutils.JaroWinklerDistance(r.Title, other.Title), 5.
+	titleR, titleW := stringutils.JaroWinklerDistance(
+		BaseTitle(r.Title), BaseTitle(other.Title)), 5.
 	perfR, perfW := r.performersCompare(other)
 	trcksR, trcksW := r.tracksCompare(other)
 	frmsR, frmsW := r.discFormatsCompare(other)
@@ -249,6 +287,9 @@ func (r *Release) tracksCompare(other *Release) (float64, float64) {
 func (r *Release) discFormatsCompare(other *Release) (float64, float64) {
 	var res, max float64
 	for i, disc := range r.Discs {
+		if i >= len(other.Discs) {
+			break
+		}
 		res = disc.Format.Compare(other.Discs[i].Format)
 		if max < res {
 			max = res
@@ -260,6 +301,121 @@ func (r *Release) discFormatsCompare(other *Release) (float64, float64) {
 	return max, 1.
 }
 
+// --- NORMALIZATION METHODS ---
+
+// Normalize исправляет несогласованность списка треков: сортирует треки по позиции,
+// связывает треки с дисками, удаляет пустые диски-заполнители, заполняет количество треков
+// каждого диска и общие количества дисков и треков релиза (см. fillTotal).
+// Возвращает перечень выполненных исправлений.
+func (r *Release) Normalize() []string {
+	var fixes []string
+	fixes = append(fixes, r.sortTracks()...)
+	fixes = append(fixes, r.linkTracksWithDiscs()...)
+	fixes = append(fixes, r.dropEmptyDiscs()...)
+	fixes = append(fixes, r.fillTotals()...)
+	return fixes
+}
+
+func (r *Release) sortTracks() []string {
+	positions := make([]TrackPosition, len(r.Tracks))
+	for i, track := range r.Tracks {
+		if track.Position == "" {
+			return nil
+		}
+		positions[i] = ParsePosition(track.Position)
+	}
+	idxs := make([]int, len(r.Tracks))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	sort.SliceStable(idxs, func(i, j int) bool {
+		return positions[idxs[i]].Less(positions[idxs[j]])
+	})
+	if sort.IntsAreSorted(idxs) {
+		return nil
+	}
+	tracks := make([]*Track, len(r.Tracks))
+	for i, idx := range idxs {
+		tracks[i] = r.Tracks[idx]
+	}
+	r.Tracks = tracks
+	return []string{"tracks sorted by position"}
+}
+
+func (r *Release) linkTracksWithDiscs() []string {
+	var fixes []string
+	for _, track := range r.Tracks {
+		num := 1
+		switch {
+		case track.Position != "":
+			num = ParsePosition(track.Position).DiscNumber()
+		case track.Disc() != nil:
+			continue
+		}
+		if d := track.Disc(); d != nil && d.Number == num {
+			continue
+		}
+		track.LinkWithDisc(r.Disc(num))
+		fixes = append(fixes, fmt.Sprintf("track %q linked to disc %d", track.Position, num))
+	}
+	return fixes
+}
+
+func (r *Release) dropEmptyDiscs() []string {
+	var fixes []string
+	used := map[*Disc]bool{}
+	for _, track := range r.Tracks {
+		used[track.Disc()] = true
+	}
+	for i := len(r.Discs) - 1; i >= 0; i-- {
+		d := r.Discs[i]
+		if used[d] || d.Title != "" || !d.Format.IsEmpty() || len(d.IDs) != 0 {
+			continue
+		}
+		r.Discs = append(r.Discs[:i], r.Discs[i+1:]...)
+		fixes = append(fixes, fmt.Sprintf("empty disc %d removed", d.Number))
+	}
+	return fixes
+}
+
+func (r *Release) fillTotals() []string {
+	var fixes []string
+	counts := map[*Disc]int{}
+	for _, track := range r.Tracks {
+		counts[track.Disc()]++
+	}
+	for _, d := range r.Discs {
+		if fix := fillTotal(&d.TotalTracks, counts[d], "tracks"); fix != "" {
+			fixes = append(fixes, fmt.Sprintf("disc %d %s", d.Number, fix))
+		}
+	}
+	if fix := fillTotal(&r.TotalDiscs, len(r.Discs), "discs"); fix != "" {
+		fixes = append(fixes, fix)
+	}
+	if fix := fillTotal(&r.TotalTracks, len(r.Tracks), "tracks"); fix != "" {
+		fixes = append(fixes, fix)
+	}
+	return fixes
+}
+
+// fillTotal заполняет незаданное или увеличивает меньшее фактического количество. Большее
+// фактического количество сохраняется (релиз может быть представлен не полностью), но
+// расхождение сообщается.
+func fillTotal(total *int, found int, what string) string {
+	switch {
+	case found == 0 || *total == found:
+		return ""
+	case *total == 0:
+		*total = found
+		return fmt.Sprintf("total %s set to %d", what, found)
+	case *total < found:
+		fix := fmt.Sprintf("total %s changed from %d to %d", what, *total, found)
+		*total = found
+		return fix
+	}
+	return fmt.Sprintf("total %s %d differs from %d found", what, *total, found)
+}
+
 // --- OPTIMIZATION METHODS ---
 
 type void struct{}
@@ -0,0 +1,65 @@
+package metadata
+
+// ReleaseEvent описывает выпуск релиза в стране или регионе на определенную дату.
+type ReleaseEvent struct {
+	Country string      `json:"country,omitempty"`
+	Date    PartialDate `json:"date"`
+}
+
+// AddEvent добавляет событие выпуска релиза. Код страны проверяется и нормализуется
+// (см. NormalizeCountry). Производные поля Year и Country обновляются по самому раннему
+// событию с известной датой.
+func (stub *ReleaseStub) AddEvent(country string, date PartialDate) error {
+	code, err := NormalizeCountry(country)
+	if err != nil {
+		return err
+	}
+	if err := date.Validate(); err != nil {
+		return err
+	}
+	for _, e := range stub.Events {
+		if e.Country == code && e.Date == date {
+			return nil
+		}
+	}
+	stub.Events = append(stub.Events, &ReleaseEvent{Country: code, Date: date})
+	stub.updateDerivedDate()
+	return nil
+}
+
+// ReleaseDate возвращает дату самого раннего события выпуска релиза. При отсутствии
+// событий возвращается дата, построенная по полю Year.
+func (stub *ReleaseStub) ReleaseDate() PartialDate {
+	if e := stub.FirstEvent(); e != nil {
+		return e.Date
+	}
+	return PartialDate{Year: stub.Year}
+}
+
+// FirstEvent возвращает самое раннее событие выпуска релиза с известной датой или nil.
+func (stub *ReleaseStub) FirstEvent() *ReleaseEvent {
+	var ret *ReleaseEvent
+	for _, e := range stub.Events {
+		if e.Date.IsEmpty() {
+			continue
+		}
+		if ret == nil || e.Date.Before(ret.Date) {
+			ret = e
+		}
+	}
+	return ret
+}
+
+func (stub *ReleaseStub) updateDerivedDate() {
+	e := stub.FirstEvent()
+	if e == nil {
+		if stub.Country == "" && len(stub.Events) > 0 {
+			stub.Country = stub.Events[0].Country
+		}
+		return
+	}
+	stub.Year = e.Date.Year
+	if e.Country != "" {
+		stub.Country = e.Country
+	}
+}
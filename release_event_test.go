@@ -0,0 +1,63 @@
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCountry(t *testing.T) {
+	results := map[string]string{
+		"gb":          "GB",
+		"UK":          "GB",
+		"USA":         "US",
+		"Europe":      CountryEurope,
+		"UK & Europe": CountryEurope,
+		"Worldwide":   CountryWorldwide,
+		"USSR":        "SU",
+		"":            "",
+	}
+	for s, expected := range results {
+		code, err := NormalizeCountry(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, code, s)
+	}
+	_, err := NormalizeCountry("Atlantis")
+	assert.Error(t, err)
+	assert.True(t, IsRegion(CountryWorldwide))
+	assert.False(t, IsRegion("GB"))
+}
+
+func TestReleaseStubAddEvent(t *testing.T) {
+	stub := NewReleaseStub()
+	require.NoError(t, stub.AddEvent("US", PartialDate{Year: 1969, Month: 10, Day: 1}))
+	require.NoError(t, stub.AddEvent("UK", PartialDate{Year: 1969, Month: 9, Day: 26}))
+	require.NoError(t, stub.AddEvent("UK", PartialDate{Year: 1969, Month: 9, Day: 26}))
+	require.NoError(t, stub.AddEvent("Europe", PartialDate{}))
+	assert.Len(t, stub.Events, 3)
+	assert.Equal(t, 1969, stub.Year)
+	assert.Equal(t, "GB", stub.Country)
+	assert.Equal(t, PartialDate{Year: 1969, Month: 9, Day: 26}, stub.ReleaseDate())
+	assert.Error(t, stub.AddEvent("Atlantis", PartialDate{Year: 1970}))
+	assert.Error(t, stub.AddEvent("US", PartialDate{Year: 1970, Month: 2, Day: 30}))
+	assert.Len(t, stub.Events, 3)
+}
+
+func TestReleaseStubReleaseDateByYear(t *testing.T) {
+	stub := NewReleaseStub()
+	stub.Year = 1971
+	assert.Equal(t, PartialDate{Year: 1971}, stub.ReleaseDate())
+}
+
+func TestReleaseEventJSON(t *testing.T) {
+	stub := NewReleaseStub()
+	require.NoError(t, stub.AddEvent("JP", PartialDate{Year: 1983, Month: 10}))
+	data, err := json.Marshal(stub.Events)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"country":"JP","date":"1983-10"}]`, string(data))
+	var events []*ReleaseEvent
+	require.NoError(t, json.Unmarshal(data, &events))
+	assert.Equal(t, stub.Events, events)
+}
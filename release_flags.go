@@ -33,6 +33,8 @@ var StrToReleaseStatus = map[string]ReleaseStatus{
 }
 
 // ReleaseType ..
+// Значения до ReleaseTypeOther включительно описывают основной тип релиза, остальные -
+// дополнительные типы (см. ReleaseType.IsPrimary).
 type ReleaseType int8
 
 // Release Type
@@ -41,6 +43,17 @@ const (
 	ReleaseTypeMaxiSingle
 	ReleaseTypeMiniAlbum
 	ReleaseTypeAlbum
+	ReleaseTypeEP
+	ReleaseTypeBroadcast
+	ReleaseTypeOther
+	ReleaseTypeSoundtrack
+	ReleaseTypeSpokenWord
+	ReleaseTypeInterview
+	ReleaseTypeAudiobook
+	ReleaseTypeAudioDrama
+	ReleaseTypeDJMix
+	ReleaseTypeMixtape
+	ReleaseTypeDemo
 )
 
 // StrToReleaseType ..
@@ -49,6 +62,17 @@ var StrToReleaseType = map[string]ReleaseType{
 	"maxisingle": ReleaseTypeMaxiSingle,
 	"minialbum":  ReleaseTypeMiniAlbum,
 	"album":      ReleaseTypeAlbum,
+	"ep":         ReleaseTypeEP,
+	"broadcast":  ReleaseTypeBroadcast,
+	"other":      ReleaseTypeOther,
+	"soundtrack": ReleaseTypeSoundtrack,
+	"spokenword": ReleaseTypeSpokenWord,
+	"interview":  ReleaseTypeInterview,
+	"audiobook":  ReleaseTypeAudiobook,
+	"audiodrama": ReleaseTypeAudioDrama,
+	"djmix":      ReleaseTypeDJMix,
+	"mixtape":    ReleaseTypeMixtape,
+	"demo":       ReleaseTypeDemo,
 }
 
 // ReleaseRepeat ..
@@ -178,6 +202,28 @@ func (rt ReleaseType) String() string {
 		return "minialbum"
 	case ReleaseTypeAlbum:
 		return "album"
+	case ReleaseTypeEP:
+		return "ep"
+	case ReleaseTypeBroadcast:
+		return "broadcast"
+	case ReleaseTypeOther:
+		return "other"
+	case ReleaseTypeSoundtrack:
+		return "soundtrack"
+	case ReleaseTypeSpokenWord:
+		return "spokenword"
+	case ReleaseTypeInterview:
+		return "interview"
+	case ReleaseTypeAudiobook:
+		return "audiobook"
+	case ReleaseTypeAudioDrama:
+		return "audiodrama"
+	case ReleaseTypeDJMix:
+		return "djmix"
+	case ReleaseTypeMixtape:
+		return "mixtape"
+	case ReleaseTypeDemo:
+		return "demo"
 	}
 	return ""
 }
@@ -323,3 +369,86 @@ func (ro *ReleaseOrigin) DecodeSlice(props *[]string) {
 		}
 	}
 }
+
+// releaseFlagSynonyms отображает распространенные обозначения признаков релиза (в т.ч.
+// сокращения Discogs) на ключи словарей StrToReleaseStatus, StrToReleaseType,
+// StrToReleaseRepeat, StrToReleaseRemake и StrToReleaseOrigin.
+var releaseFlagSynonyms = map[string][]string{
+	"official":           {"oficial"},
+	"promo":              {"promotion"},
+	"unofficial release": {"bootleg"},
+	"test pressing":      {"demonstration"},
+	"lp":                 {"album"},
+	"comp":               {"compilation"},
+	"re":                 {"reissue"},
+	"rp":                 {"repress"},
+	"remaster":           {"remastered"},
+	"rm":                 {"remastered"},
+	"field recording":    {"fieldrecording"},
+}
+
+// DecodeFlags разбирает перечень свойств релиза в свободной форме (например, описание
+// формата Discogs ["Album", "Reissue", "Remastered", "Stereo", "180g"]) и устанавливает
+// признаки релиза, тип медиа и свойства носителя с учетом синонимов.
+// Свойства носителя и нераспознанные свойства (DiscFormat.Attrs) добавляются в форматы всех
+// дисков релиза. Если диски отсутствуют, создается первый диск.
+func (stub *ReleaseStub) DecodeFlags(props []string) {
+	var mediaProps []string
+	for _, prop := range props {
+		if prop = strings.TrimSpace(prop); prop == "" {
+			continue
+		}
+		if isFlag := stub.decodeFlag(prop); !isFlag || DecodeMedia(prop) != 0 {
+			mediaProps = append(mediaProps, prop)
+		}
+	}
+	if len(mediaProps) == 0 {
+		return
+	}
+	format := DecodeDiscFormat(mediaProps)
+	if len(stub.Discs) == 0 {
+		stub.Discs = append(stub.Discs, NewDisc(1))
+	}
+	for _, d := range stub.Discs {
+		if d.Format == nil {
+			d.Format = &DiscFormat{}
+		}
+		d.Format.Merge(format)
+	}
+}
+
+func (stub *ReleaseStub) decodeFlag(prop string) bool {
+	lowerProp := strings.ToLower(prop)
+	if v, ok := DiscogsReleaseTypes[lowerProp]; ok {
+		stub.SetReleaseType(v)
+		return true
+	}
+	keys, ok := releaseFlagSynonyms[lowerProp]
+	if !ok {
+		keys = []string{lowerProp}
+	}
+	var matched bool
+	for _, key := range keys {
+		if v, ok := StrToReleaseStatus[key]; ok {
+			stub.ReleaseStatus = v
+			matched = true
+		}
+		if v, ok := StrToReleaseType[key]; ok {
+			stub.SetReleaseType(v)
+			matched = true
+		}
+		if v, ok := StrToReleaseRepeat[key]; ok {
+			stub.ReleaseRepeat = v
+			matched = true
+		}
+		if v, ok := StrToReleaseRemake[key]; ok {
+			stub.ReleaseRemake = v
+			matched = true
+		}
+		if v, ok := StrToReleaseOrigin[key]; ok {
+			stub.ReleaseOrigin = v
+			matched = true
+		}
+	}
+	return matched
+}
@@ -100,3 +100,31 @@ func TestReleaseOriginDecodeSlice(t *testing.T) {
 	ro.DecodeSlice(&[]string{"Text", "Studio", "Another Text"})
 	assert.Equal(t, ReleaseOriginStudio, ro)
 }
+
+func TestReleaseStubDecodeFlags(t *testing.T) {
+	stub := NewReleaseStub()
+	stub.DecodeFlags([]string{
+		"Vinyl", "LP", "Album", "Reissue", "Remastered", "Stereo", "180g", "Gatefold"})
+	assert.Equal(t, ReleaseTypeAlbum, stub.ReleaseType)
+	assert.Equal(t, ReleaseRepeatReissue, stub.ReleaseRepeat)
+	assert.Equal(t, ReleaseRemakeRemastered, stub.ReleaseRemake)
+	require.Len(t, stub.Discs, 1)
+	assert.Equal(t, MediaLP, stub.Discs[0].Format.Media)
+	assert.Equal(t, ChannelModeStereo, stub.Discs[0].Format.ChannelMode)
+	assert.Equal(t, 180, stub.Discs[0].Format.Weight)
+	assert.Equal(t, []string{"Gatefold"}, stub.Discs[0].Format.Attrs)
+
+	stub = NewReleaseStub()
+	stub.Discs = []*Disc{NewDisc(1), NewDisc(2)}
+	stub.DecodeFlags([]string{"Official", "EP", "Live", "Comp", "CD"})
+	assert.Equal(t, ReleaseStatusOfficial, stub.ReleaseStatus)
+	assert.Equal(t, ReleaseTypeEP, stub.ReleaseType)
+	assert.Equal(t, ReleaseOriginLive, stub.ReleaseOrigin)
+	assert.Equal(t, ReleaseRepeatCompilation, stub.ReleaseRepeat)
+	assert.Equal(t, MediaCD, stub.Discs[1].Format.Media)
+	assert.Empty(t, stub.Discs[1].Format.Attrs)
+
+	stub = NewReleaseStub()
+	stub.DecodeFlags([]string{"Album"})
+	assert.Empty(t, stub.Discs)
+}
@@ -35,6 +35,51 @@ func TestReleaseDisc(t *testing.T) {
 	d := r.Disc(3)
 	assert.Len(t, r.Discs, 3)
 	assert.NotEmpty(t, d)
+	assert.Equal(t, 3, d.Number)
+	assert.Equal(t, 2, r.Discs[1].Number)
+	r.Discs = []*Disc{r.Discs[0], r.Discs[2]}
+	assert.Equal(t, 3, r.Disc(3).Number)
+	assert.Equal(t, 2, r.Disc(2).Number)
+	assert.Len(t, r.Discs, 3)
+}
+
+func TestReleaseNormalize(t *testing.T) {
+	r := NewRelease()
+	for _, pos := range []string{"C1", "A2", "A1", "B1", "C2"} {
+		track := NewTrack()
+		track.Position = pos
+		r.Tracks = append(r.Tracks, track)
+	}
+	r.TotalTracks = 12
+	r.Disc(2).TotalTracks = 7
+	r.Disc(3)
+	fixes := r.Normalize()
+	assert.NotEmpty(t, fixes)
+	assert.Equal(t, "A1", r.Tracks[0].Position)
+	assert.Equal(t, "C2", r.Tracks[4].Position)
+	require.Len(t, r.Discs, 2)
+	assert.Equal(t, 2, r.Discs[1].Number)
+	assert.Equal(t, 3, r.Discs[0].TotalTracks)
+	assert.Equal(t, 7, r.Discs[1].TotalTracks)
+	assert.Same(t, r.Discs[1], r.Tracks[3].Disc())
+	assert.Equal(t, 2, r.TotalDiscs)
+	assert.Equal(t, 12, r.TotalTracks)
+	assert.Contains(t, fixes, "empty disc 3 removed")
+	assert.Contains(t, fixes, "disc 1 total tracks set to 3")
+	assert.Contains(t, fixes, "total discs set to 2")
+	assert.Contains(t, fixes, "disc 2 total tracks 7 differs from 2 found")
+	assert.Equal(t, []string{
+		"disc 2 total tracks 7 differs from 2 found",
+		"total tracks 12 differs from 5 found"}, r.Normalize())
+
+	r.TotalTracks = 4
+	r.TotalDiscs = 3
+	assert.Equal(t, []string{
+		"disc 2 total tracks 7 differs from 2 found",
+		"total discs 3 differs from 2 found",
+		"total tracks changed from 4 to 5"}, r.Normalize())
+	assert.Equal(t, 5, r.TotalTracks)
+	assert.Equal(t, 3, r.TotalDiscs)
 }
 
 func TestReleasePerformersCompare(t *testing.T) {
@@ -59,7 +104,25 @@ func TestReleasePubCompare(t *testing.T) {
 	r2.Publishing.AddLabel(NewLabel("RCA", ""))
 	r2.Publishing.AddLabel(NewLabel("Analog Audio", ""))
 	res, weight = r.pubCompare(r2)
-	assert.True(t, res == 0. && weight == 1.)
+	assert.True(t, res < 1. && weight == 1.)
+}
+
+func TestReleaseCompareLabelsWithoutCatno(t *testing.T) {
+	r := NewRelease()
+	r.Title = "Blue Train"
+	r.Publishing.AddLabel(NewLabel("Blue Note", ""))
+	r2 := NewRelease()
+	r2.Title = "Somethin' Else"
+	r2.Publishing.AddLabel(NewLabel("Blue Note Records", ""))
+	assert.Less(t, r.Compare(r2), 1.)
+
+	r.Publishing.Labels[0].Catno = "1"
+	r2.Publishing.Labels[0] = NewLabel("Columbia", "1")
+	assert.Less(t, r.Compare(r2), 1.)
+
+	r2.Title = r.Title
+	r2.Publishing.Labels[0] = NewLabel("Blue Note Records", "1")
+	assert.Equal(t, 1., r.Compare(r2))
 }
 
 func TestReleaseTracksCompare(t *testing.T) {
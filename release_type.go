@@ -0,0 +1,90 @@
+package metadata
+
+import "strings"
+
+// MusicbrainzReleaseTypes отображает основные и дополнительные типы группы релизов
+// MusicBrainz на значения ReleaseType.
+// Дополнительные типы compilation, live, remix и field recording отражаются признаками
+// ReleaseRepeat, ReleaseOrigin и ReleaseRemake (см. ReleaseStub.DecodeMusicbrainzTypes).
+// https://musicbrainz.org/doc/Release_Group/Type
+var MusicbrainzReleaseTypes = map[string]ReleaseType{
+	"album":          ReleaseTypeAlbum,
+	"single":         ReleaseTypeSingle,
+	"ep":             ReleaseTypeEP,
+	"broadcast":      ReleaseTypeBroadcast,
+	"other":          ReleaseTypeOther,
+	"soundtrack":     ReleaseTypeSoundtrack,
+	"spokenword":     ReleaseTypeSpokenWord,
+	"interview":      ReleaseTypeInterview,
+	"audiobook":      ReleaseTypeAudiobook,
+	"audio drama":    ReleaseTypeAudioDrama,
+	"dj-mix":         ReleaseTypeDJMix,
+	"mixtape/street": ReleaseTypeMixtape,
+	"demo":           ReleaseTypeDemo,
+}
+
+// DiscogsReleaseTypes отображает описания форматов и стили Discogs на значения ReleaseType.
+var DiscogsReleaseTypes = map[string]ReleaseType{
+	"album":       ReleaseTypeAlbum,
+	"single":      ReleaseTypeSingle,
+	"ep":          ReleaseTypeEP,
+	"mini-album":  ReleaseTypeMiniAlbum,
+	"maxi-single": ReleaseTypeMaxiSingle,
+	"mixtape":     ReleaseTypeMixtape,
+	"dj-mix":      ReleaseTypeDJMix,
+	"soundtrack":  ReleaseTypeSoundtrack,
+	"score":       ReleaseTypeSoundtrack,
+	"spoken word": ReleaseTypeSpokenWord,
+	"interview":   ReleaseTypeInterview,
+	"audiobook":   ReleaseTypeAudiobook,
+	"radioplay":   ReleaseTypeAudioDrama,
+	"demo":        ReleaseTypeDemo,
+}
+
+// IsPrimary проверяет, является ли тип основным типом релиза.
+func (rt ReleaseType) IsPrimary() bool {
+	return rt >= ReleaseTypeSingle && rt <= ReleaseTypeOther
+}
+
+// SetReleaseType устанавливает основной тип релиза или добавляет дополнительный тип.
+func (stub *ReleaseStub) SetReleaseType(rt ReleaseType) {
+	switch {
+	case rt.IsPrimary():
+		stub.ReleaseType = rt
+	case rt != 0 && !stub.HasReleaseType(rt):
+		stub.SecondaryTypes = append(stub.SecondaryTypes, rt)
+	}
+}
+
+// HasReleaseType проверяет наличие у релиза основного или дополнительного типа.
+func (stub *ReleaseStub) HasReleaseType(rt ReleaseType) bool {
+	if stub.ReleaseType == rt {
+		return true
+	}
+	for _, secondary := range stub.SecondaryTypes {
+		if secondary == rt {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeMusicbrainzTypes устанавливает типы релиза по основному и дополнительным типам
+// группы релизов MusicBrainz.
+func (stub *ReleaseStub) DecodeMusicbrainzTypes(primary string, secondary []string) {
+	for _, t := range append([]string{primary}, secondary...) {
+		t = strings.ToLower(strings.TrimSpace(t))
+		switch t {
+		case "compilation":
+			stub.ReleaseRepeat = ReleaseRepeatCompilation
+		case "live":
+			stub.ReleaseOrigin = ReleaseOriginLive
+		case "remix":
+			stub.ReleaseRemake = ReleaseRemakeRemix
+		case "field recording":
+			stub.ReleaseOrigin = ReleaseOriginFieldRecording
+		default:
+			stub.SetReleaseType(MusicbrainzReleaseTypes[t])
+		}
+	}
+}
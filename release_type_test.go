@@ -0,0 +1,55 @@
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseTypeIsPrimary(t *testing.T) {
+	assert.True(t, ReleaseTypeAlbum.IsPrimary())
+	assert.True(t, ReleaseTypeEP.IsPrimary())
+	assert.False(t, ReleaseTypeSoundtrack.IsPrimary())
+	assert.False(t, ReleaseType(0).IsPrimary())
+}
+
+func TestReleaseStubSetReleaseType(t *testing.T) {
+	stub := NewReleaseStub()
+	stub.SetReleaseType(ReleaseTypeAlbum)
+	stub.SetReleaseType(ReleaseTypeSoundtrack)
+	stub.SetReleaseType(ReleaseTypeSoundtrack)
+	stub.SetReleaseType(0)
+	assert.Equal(t, ReleaseTypeAlbum, stub.ReleaseType)
+	assert.Equal(t, []ReleaseType{ReleaseTypeSoundtrack}, stub.SecondaryTypes)
+	assert.True(t, stub.HasReleaseType(ReleaseTypeSoundtrack))
+	assert.False(t, stub.HasReleaseType(ReleaseTypeDemo))
+}
+
+func TestReleaseStubDecodeMusicbrainzTypes(t *testing.T) {
+	stub := NewReleaseStub()
+	stub.DecodeMusicbrainzTypes("Album", []string{"Compilation", "DJ-mix", "Live"})
+	assert.Equal(t, ReleaseTypeAlbum, stub.ReleaseType)
+	assert.Equal(t, []ReleaseType{ReleaseTypeDJMix}, stub.SecondaryTypes)
+	assert.Equal(t, ReleaseRepeatCompilation, stub.ReleaseRepeat)
+	assert.Equal(t, ReleaseOriginLive, stub.ReleaseOrigin)
+}
+
+func TestReleaseStubDecodeDiscogsTypes(t *testing.T) {
+	stub := NewReleaseStub()
+	stub.DecodeFlags([]string{"Mini-Album", "Mixtape"})
+	assert.Equal(t, ReleaseTypeMiniAlbum, stub.ReleaseType)
+	assert.Equal(t, []ReleaseType{ReleaseTypeMixtape}, stub.SecondaryTypes)
+}
+
+func TestReleaseTypesJSON(t *testing.T) {
+	stub := &ReleaseStub{ReleaseType: ReleaseTypeEP, SecondaryTypes: []ReleaseType{ReleaseTypeDemo}}
+	data, err := json.Marshal(stub)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"release_type":"ep","release_secondary_types":["demo"]`)
+	other := &ReleaseStub{}
+	require.NoError(t, json.Unmarshal([]byte(`{"title":"X","release_type":"album"}`), other))
+	assert.Equal(t, ReleaseTypeAlbum, other.ReleaseType)
+	assert.Empty(t, other.SecondaryTypes)
+}
@@ -0,0 +1,309 @@
+package metadata
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RoleGroup описывает группу ролей акторов в таксономии ролей.
+type RoleGroup int8
+
+// Группы ролей акторов.
+const (
+	RoleGroupPerformer RoleGroup = iota + 1
+	RoleGroupInstrument
+	RoleGroupVocal
+	RoleGroupConducting
+	RoleGroupComposition
+	RoleGroupArrangement
+	RoleGroupProduction
+	RoleGroupEngineering
+	RoleGroupArtwork
+	RoleGroupOther
+)
+
+// Канонические значения основных ролей акторов.
+// Для инструменталистов канонической ролью является наименование инструмента.
+const (
+	RolePerformer         ActorRole = "performer"
+	RoleFeatured          ActorRole = "featured"
+	RoleOrchestra         ActorRole = "orchestra"
+	RoleChoir             ActorRole = "choir"
+	RoleVocals            ActorRole = "vocals"
+	RoleLeadVocals        ActorRole = "lead vocals"
+	RoleBackingVocals     ActorRole = "backing vocals"
+	RoleConductor         ActorRole = "conductor"
+	RoleChorusMaster      ActorRole = "chorus master"
+	RoleComposer          ActorRole = "composer"
+	RoleLyricist          ActorRole = "lyricist"
+	RoleWriter            ActorRole = "writer"
+	RoleArranger          ActorRole = "arranger"
+	RoleOrchestrator      ActorRole = "orchestrator"
+	RoleProducer          ActorRole = "producer"
+	RoleExecutiveProducer ActorRole = "executive producer"
+	RoleRemixer           ActorRole = "remixer"
+	RoleEngineer          ActorRole = "engineer"
+	RoleRecordingEngineer ActorRole = "recording engineer"
+	RoleMixingEngineer    ActorRole = "mixing engineer"
+	RoleMasteringEngineer ActorRole = "mastering engineer"
+	RoleLacquerCut        ActorRole = "lacquer cut"
+	RoleDesign            ActorRole = "design"
+	RolePhotography       ActorRole = "photography"
+	RoleLinerNotes        ActorRole = "liner notes"
+)
+
+// RoleTaxonomy хранит группы канонических ролей.
+var RoleTaxonomy = map[ActorRole]RoleGroup{
+	RolePerformer:         RoleGroupPerformer,
+	RoleFeatured:          RoleGroupPerformer,
+	RoleOrchestra:         RoleGroupPerformer,
+	RoleChoir:             RoleGroupPerformer,
+	RoleVocals:            RoleGroupVocal,
+	RoleLeadVocals:        RoleGroupVocal,
+	RoleBackingVocals:     RoleGroupVocal,
+	"soprano vocals":      RoleGroupVocal,
+	"alto vocals":         RoleGroupVocal,
+	"tenor vocals":        RoleGroupVocal,
+	"baritone vocals":     RoleGroupVocal,
+	"bass vocals":         RoleGroupVocal,
+	"rap":                 RoleGroupVocal,
+	RoleConductor:         RoleGroupConducting,
+	RoleChorusMaster:      RoleGroupConducting,
+	RoleComposer:          RoleGroupComposition,
+	RoleLyricist:          RoleGroupComposition,
+	RoleWriter:            RoleGroupComposition,
+	RoleArranger:          RoleGroupArrangement,
+	RoleOrchestrator:      RoleGroupArrangement,
+	RoleProducer:          RoleGroupProduction,
+	RoleExecutiveProducer: RoleGroupProduction,
+	RoleRemixer:           RoleGroupProduction,
+	RoleEngineer:          RoleGroupEngineering,
+	RoleRecordingEngineer: RoleGroupEngineering,
+	RoleMixingEngineer:    RoleGroupEngineering,
+	RoleMasteringEngineer: RoleGroupEngineering,
+	RoleLacquerCut:        RoleGroupEngineering,
+	RoleDesign:            RoleGroupArtwork,
+	RolePhotography:       RoleGroupArtwork,
+	RoleLinerNotes:        RoleGroupOther,
+	// инструменты
+	"accordion":          RoleGroupInstrument,
+	"bass":               RoleGroupInstrument,
+	"bass guitar":        RoleGroupInstrument,
+	"double bass":        RoleGroupInstrument,
+	"bassoon":            RoleGroupInstrument,
+	"cello":              RoleGroupInstrument,
+	"clarinet":           RoleGroupInstrument,
+	"bass clarinet":      RoleGroupInstrument,
+	"drums":              RoleGroupInstrument,
+	"percussion":         RoleGroupInstrument,
+	"flute":              RoleGroupInstrument,
+	"flugelhorn":         RoleGroupInstrument,
+	"french horn":        RoleGroupInstrument,
+	"guitar":             RoleGroupInstrument,
+	"acoustic guitar":    RoleGroupInstrument,
+	"electric guitar":    RoleGroupInstrument,
+	"harmonica":          RoleGroupInstrument,
+	"harp":               RoleGroupInstrument,
+	"harpsichord":        RoleGroupInstrument,
+	"keyboard":           RoleGroupInstrument,
+	"oboe":               RoleGroupInstrument,
+	"organ":              RoleGroupInstrument,
+	"piano":              RoleGroupInstrument,
+	"electric piano":     RoleGroupInstrument,
+	"saxophone":          RoleGroupInstrument,
+	"soprano saxophone":  RoleGroupInstrument,
+	"alto saxophone":     RoleGroupInstrument,
+	"tenor saxophone":    RoleGroupInstrument,
+	"baritone saxophone": RoleGroupInstrument,
+	"synthesizer":        RoleGroupInstrument,
+	"trombone":           RoleGroupInstrument,
+	"trumpet":            RoleGroupInstrument,
+	"tuba":               RoleGroupInstrument,
+	"vibraphone":         RoleGroupInstrument,
+	"viola":              RoleGroupInstrument,
+	"violin":             RoleGroupInstrument,
+}
+
+// DiscogsRoles отображает наименования ролей в титрах Discogs на канонические роли.
+// Наименования ролей, совпадающие с каноническими, не перечисляются.
+// https://www.discogs.com/help/creditslist
+var DiscogsRoles = map[string]ActorRole{
+	"performer":                RolePerformer,
+	"musician":                 RolePerformer,
+	"featuring":                RoleFeatured,
+	"choir":                    RoleChoir,
+	"chorus":                   RoleChoir,
+	"vocals":                   RoleVocals,
+	"voice":                    RoleVocals,
+	"lead vocals":              RoleLeadVocals,
+	"backing vocals":           RoleBackingVocals,
+	"soprano vocals":           "soprano vocals",
+	"tenor vocals":             "tenor vocals",
+	"chorus master":            RoleChorusMaster,
+	"composed by":              RoleComposer,
+	"music by":                 RoleComposer,
+	"lyrics by":                RoleLyricist,
+	"words by":                 RoleLyricist,
+	"written-by":               RoleWriter,
+	"songwriter":               RoleWriter,
+	"arranged by":              RoleArranger,
+	"orchestrated by":          RoleOrchestrator,
+	"producer":                 RoleProducer,
+	"co-producer":              RoleProducer,
+	"produced by":              RoleProducer,
+	"executive-producer":       RoleExecutiveProducer,
+	"remix":                    RoleRemixer,
+	"engineer":                 RoleEngineer,
+	"recorded by":              RoleRecordingEngineer,
+	"recording engineer":       RoleRecordingEngineer,
+	"mixed by":                 RoleMixingEngineer,
+	"mastered by":              RoleMasteringEngineer,
+	"remastered by":            RoleMasteringEngineer,
+	"lacquer cut by":           RoleLacquerCut,
+	"design":                   RoleDesign,
+	"artwork":                  RoleDesign,
+	"photography by":           RolePhotography,
+	"liner notes":              RoleLinerNotes,
+	"acoustic bass":            "double bass",
+	"upright bass":             "double bass",
+	"contrabass":               "double bass",
+	"electric bass":            "bass guitar",
+	"keyboards":                "keyboard",
+	"sax":                      "saxophone",
+	"tenor sax":                "tenor saxophone",
+	"alto sax":                 "alto saxophone",
+	"soprano sax":              "soprano saxophone",
+	"baritone sax":             "baritone saxophone",
+	"synth":                    "synthesizer",
+	"horn":                     "french horn",
+	"piano [electric]":         "electric piano",
+	"guitar [acoustic]":        "acoustic guitar",
+	"guitar [electric]":        "electric guitar",
+	"bass [electric]":          "bass guitar",
+	"bass [acoustic]":          "double bass",
+	"drums [drum kit]":         "drums",
+	"percussion [instruments]": "percussion",
+}
+
+// MusicbrainzRoles отображает типы связей MusicBrainz на канонические роли.
+// Атрибуты связей instrument и vocal ("instrument:tenor saxophone") обрабатываются
+// отдельно в CanonicalRole.
+// https://musicbrainz.org/relationships/artist-recording
+var MusicbrainzRoles = map[string]ActorRole{
+	"performer":            RolePerformer,
+	"performing orchestra": RoleOrchestra,
+	"instrument":           RolePerformer,
+	"vocal":                RoleVocals,
+	"conductor":            RoleConductor,
+	"chorus master":        RoleChorusMaster,
+	"composer":             RoleComposer,
+	"lyricist":             RoleLyricist,
+	"librettist":           RoleLyricist,
+	"writer":               RoleWriter,
+	"arranger":             RoleArranger,
+	"instrument arranger":  RoleArranger,
+	"vocal arranger":       RoleArranger,
+	"orchestrator":         RoleOrchestrator,
+	"producer":             RoleProducer,
+	"remixer":              RoleRemixer,
+	"engineer":             RoleEngineer,
+	"audio engineer":       RoleEngineer,
+	"recording engineer":   RoleRecordingEngineer,
+	"recording":            RoleRecordingEngineer,
+	"mix":                  RoleMixingEngineer,
+	"mastering":            RoleMasteringEngineer,
+	"design/illustration":  RoleDesign,
+	"design":               RoleDesign,
+	"photography":          RolePhotography,
+	"liner notes":          RoleLinerNotes,
+}
+
+var roleQualifier = regexp.MustCompile(`\s*\[[^\]]*\]`)
+
+// CanonicalRole приводит наименование роли Discogs, MusicBrainz или произвольное
+// наименование к канонической форме.
+// Нераспознанные роли возвращаются в нижнем регистре без уточнений в квадратных скобках.
+func CanonicalRole(role string) ActorRole {
+	role = strings.ToLower(strings.Join(strings.Fields(role), " "))
+	if role == "" {
+		return ""
+	}
+	for _, prefix := range []string{"instrument:", "vocal:"} {
+		if strings.HasPrefix(role, prefix) {
+			attr := strings.TrimSpace(role[len(prefix):])
+			if canonical, ok := DiscogsRoles[attr]; ok {
+				return canonical
+			}
+			return attr
+		}
+	}
+	if canonical, ok := DiscogsRoles[role]; ok {
+		return canonical
+	}
+	if canonical, ok := MusicbrainzRoles[role]; ok {
+		return canonical
+	}
+	role = roleQualifier.ReplaceAllString(role, "")
+	if canonical, ok := DiscogsRoles[role]; ok {
+		return canonical
+	}
+	return role
+}
+
+// RoleGroupOf возвращает группу роли или 0, если роль отсутствует в таксономии.
+// Роли с атрибутами MusicBrainz "instrument:" и "vocal:" относятся к группам
+// инструменталистов и вокалистов даже при отсутствии атрибута в таксономии.
+func RoleGroupOf(role string) RoleGroup {
+	lowerRole := strings.ToLower(strings.TrimSpace(role))
+	switch {
+	case strings.HasPrefix(lowerRole, "instrument:"):
+		return RoleGroupInstrument
+	case strings.HasPrefix(lowerRole, "vocal:"):
+		return RoleGroupVocal
+	}
+	return RoleTaxonomy[CanonicalRole(role)]
+}
+
+// SameRole проверяет, описывают ли два наименования роли одну и ту же роль.
+func SameRole(role1, role2 string) bool {
+	return CanonicalRole(role1) == CanonicalRole(role2)
+}
+
+func hasRoleGroup(roles []ActorRole, groups ...RoleGroup) bool {
+	for _, role := range roles {
+		group := RoleGroupOf(role)
+		for _, g := range groups {
+			if group == g {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsPerformer предикатная функция фильтрации исполнителей альбома.
+func IsPerformer(name ActorName, roles []ActorRole) bool {
+	return hasRoleGroup(roles, RoleGroupPerformer)
+}
+
+// IsMusician предикатная функция фильтрации всех участников исполнения: исполнителей,
+// инструменталистов, вокалистов и дирижеров.
+func IsMusician(name ActorName, roles []ActorRole) bool {
+	return hasRoleGroup(
+		roles, RoleGroupPerformer, RoleGroupInstrument, RoleGroupVocal, RoleGroupConducting)
+}
+
+// IsComposer предикатная функция фильтрации авторов музыки и текста.
+func IsComposer(name ActorName, roles []ActorRole) bool {
+	return hasRoleGroup(roles, RoleGroupComposition)
+}
+
+// IsProducer предикатная функция фильтрации продюсеров.
+func IsProducer(name ActorName, roles []ActorRole) bool {
+	return hasRoleGroup(roles, RoleGroupProduction)
+}
+
+// IsEngineer предикатная функция фильтрации звукоинженеров.
+func IsEngineer(name ActorName, roles []ActorRole) bool {
+	return hasRoleGroup(roles, RoleGroupEngineering)
+}
@@ -0,0 +1,47 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalRole(t *testing.T) {
+	assert.Equal(t, ActorRole("tenor saxophone"), CanonicalRole("Tenor Saxophone"))
+	assert.Equal(t, ActorRole("tenor saxophone"), CanonicalRole("instrument:tenor saxophone"))
+	assert.Equal(t, ActorRole("tenor saxophone"), CanonicalRole("Tenor Sax"))
+	assert.Equal(t, ActorRole("bass guitar"), CanonicalRole("Bass [Electric]"))
+	assert.Equal(t, ActorRole("cello"), CanonicalRole("Cello [Baroque]"))
+	assert.Equal(t, RoleComposer, CanonicalRole("Composed By"))
+	assert.Equal(t, RoleMixingEngineer, CanonicalRole("mix"))
+	assert.Equal(t, ActorRole("theremin"), CanonicalRole("Theremin"))
+	assert.Empty(t, CanonicalRole(" "))
+}
+
+func TestRoleGroupOf(t *testing.T) {
+	assert.Equal(t, RoleGroupInstrument, RoleGroupOf("Tenor Saxophone"))
+	assert.Equal(t, RoleGroupInstrument, RoleGroupOf("instrument:theremin"))
+	assert.Equal(t, RoleGroupVocal, RoleGroupOf("Lead Vocals"))
+	assert.Equal(t, RoleGroupProduction, RoleGroupOf("Executive-Producer"))
+	assert.Equal(t, RoleGroup(0), RoleGroupOf("unknown"))
+}
+
+func TestSameRole(t *testing.T) {
+	assert.True(t, SameRole("Tenor Saxophone", "instrument:tenor saxophone"))
+	assert.True(t, SameRole("Written-By", "writer"))
+	assert.False(t, SameRole("saxophone", "tenor saxophone"))
+}
+
+func TestRolePredicates(t *testing.T) {
+	ar := ActorRoles{
+		"Miles Davis":   {"performer", "Trumpet"},
+		"John Coltrane": {"Tenor Saxophone"},
+		"Teo Macero":    {"Producer"},
+		"Nemo":          {"Composed By"},
+	}
+	assert.Len(t, ar.Filter(IsPerformer), 1)
+	assert.Len(t, ar.Filter(IsMusician), 2)
+	assert.Len(t, ar.Filter(IsProducer), 1)
+	assert.Len(t, ar.Filter(IsComposer), 1)
+	assert.Empty(t, ar.Filter(IsEngineer))
+}
@@ -0,0 +1,146 @@
+package metadata
+
+import "strings"
+
+// RecordSession описывает сессию записи: место и период записи, характер записи
+// (студийная, концертная и т.д.), а также участников сессии (звукоинженеров,
+// технический персонал студии), роли которых относятся только к данной сессии.
+type RecordSession struct {
+	Venue      string        `json:"venue,omitempty"`
+	City       string        `json:"city,omitempty"`
+	Country    string        `json:"country,omitempty"`
+	Dates      *DateRange    `json:"dates,omitempty"`
+	Origin     ReleaseOrigin `json:"origin,omitempty"`
+	ActorRoles ActorRoles    `json:"actor_roles,omitempty"`
+	Notes      string        `json:"notes,omitempty"`
+}
+
+// NewRecordSession создает новый объект RecordSession.
+func NewRecordSession() *RecordSession {
+	return &RecordSession{ActorRoles: ActorRoles{}}
+}
+
+// SetPlace устанавливает место записи. Код страны проверяется и нормализуется
+// (см. NormalizeCountry).
+func (rs *RecordSession) SetPlace(venue, city, country string) error {
+	code, err := NormalizeCountry(country)
+	if err != nil {
+		return err
+	}
+	rs.Venue, rs.City, rs.Country = strings.TrimSpace(venue), strings.TrimSpace(city), code
+	return nil
+}
+
+// SetDates устанавливает период записи.
+func (rs *RecordSession) SetDates(begin, end PartialDate) error {
+	dr, err := NewDateRange(begin, end)
+	if err != nil {
+		return err
+	}
+	rs.Dates = dr
+	return nil
+}
+
+// Place возвращает описание места записи вида "Venue, City, Country".
+func (rs *RecordSession) Place() string {
+	var parts []string
+	for _, part := range []string{rs.Venue, rs.City, rs.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// IsLive проверяет, является ли сессия концертной записью.
+func (rs *RecordSession) IsLive() bool {
+	return rs.Origin == ReleaseOriginLive
+}
+
+// IsEmpty проверяет отсутствие сведений о сессии.
+func (rs *RecordSession) IsEmpty() bool {
+	return rs.Venue == "" && rs.City == "" && rs.Country == "" && rs.Dates.IsEmpty() &&
+		rs.Origin == 0 && rs.ActorRoles.IsEmpty() && rs.Notes == ""
+}
+
+// Clean сбрасывает пустые коллекции сессии в nil.
+func (rs *RecordSession) Clean() {
+	rs.ActorRoles.Clean()
+	if rs.ActorRoles.IsEmpty() {
+		rs.ActorRoles = nil
+	}
+	if rs.Dates.IsEmpty() {
+		rs.Dates = nil
+	}
+}
+
+// AddSession добавляет сессию записи.
+func (p *Record) AddSession(session *RecordSession) {
+	p.Sessions = append(p.Sessions, session)
+}
+
+func (p *Record) cleanSessions() {
+	var sessions []*RecordSession
+	for _, session := range p.Sessions {
+		session.Clean()
+		if !session.IsEmpty() {
+			sessions = append(sessions, session)
+		}
+	}
+	p.Sessions = sessions
+}
+
+// SessionRoles возвращает объединенные роли участников всех сессий записи.
+func (p *Record) SessionRoles() ActorRoles {
+	ret := ActorRoles{}
+	for _, session := range p.Sessions {
+		for name, roles := range session.ActorRoles {
+			for _, role := range roles {
+				ret.Add(name, role)
+			}
+		}
+	}
+	return ret
+}
+
+// IsLive проверяет, что запись содержит сессии и все они концертные.
+func (p *Record) IsLive() bool {
+	for _, session := range p.Sessions {
+		if !session.IsLive() {
+			return false
+		}
+	}
+	return len(p.Sessions) > 0
+}
+
+// InferLiveOrigin определяет по сессиям записей треков, является ли релиз концертным.
+// Если все треки со сведениями о сессиях записаны на концертах, незаполненный признак
+// ReleaseOrigin устанавливается в ReleaseOriginLive, и возвращается место записи
+// (при записи в нескольких местах - перечень мест через "; ").
+func (r *Release) InferLiveOrigin() (string, bool) {
+	var places []string
+	var live bool
+	seen := map[string]bool{}
+	for _, track := range r.Tracks {
+		if track.Record == nil || len(track.Record.Sessions) == 0 {
+			continue
+		}
+		if !track.Record.IsLive() {
+			return "", false
+		}
+		live = true
+		for _, session := range track.Record.Sessions {
+			if place := session.Place(); place != "" && !seen[place] {
+				seen[place] = true
+				places = append(places, place)
+			}
+		}
+	}
+	if !live {
+		return "", false
+	}
+	if r.ReleaseOrigin == 0 {
+		r.ReleaseOrigin = ReleaseOriginLive
+	}
+	return strings.Join(places, "; "), true
+}
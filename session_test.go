@@ -0,0 +1,95 @@
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func liveSession(t *testing.T, venue, city, country string) *RecordSession {
+	s := NewRecordSession()
+	require.NoError(t, s.SetPlace(venue, city, country))
+	s.Origin = ReleaseOriginLive
+	return s
+}
+
+func TestRecordSessionPlace(t *testing.T) {
+	s := NewRecordSession()
+	require.NoError(t, s.SetPlace("Abbey Road Studios", "London", "UK"))
+	assert.Equal(t, "GB", s.Country)
+	assert.Equal(t, "Abbey Road Studios, London, GB", s.Place())
+	assert.Error(t, s.SetPlace("Studio", "City", "Atlantis"))
+}
+
+func TestRecordSessionDates(t *testing.T) {
+	s := NewRecordSession()
+	require.NoError(t, s.SetDates(
+		PartialDate{Year: 1969, Month: 2}, PartialDate{Year: 1969, Month: 8, Day: 20}))
+	assert.True(t, s.Dates.Contains(PartialDate{Year: 1969, Month: 4, Day: 16}))
+	assert.False(t, s.Dates.Contains(PartialDate{Year: 1969, Month: 9}))
+	assert.Error(t, s.SetDates(PartialDate{Year: 1970}, PartialDate{Year: 1969}))
+}
+
+func TestParseDateRange(t *testing.T) {
+	dr, err := ParseDateRange("1969-09-26/1969-09-27")
+	require.NoError(t, err)
+	assert.Equal(t, PartialDate{Year: 1969, Month: 9, Day: 27}, dr.End)
+	assert.Equal(t, "1969-09-26/1969-09-27", dr.String())
+	dr, err = ParseDateRange("1969")
+	require.NoError(t, err)
+	assert.True(t, dr.Contains(PartialDate{Year: 1969, Month: 3}))
+	_, err = ParseDateRange("1970/1969")
+	assert.Error(t, err)
+}
+
+func TestRecordSessionJSON(t *testing.T) {
+	s := liveSession(t, "Fillmore East", "New York", "US")
+	require.NoError(t, s.SetDates(PartialDate{Year: 1970, Month: 3, Day: 6}, PartialDate{}))
+	s.ActorRoles.Add("Stan Tonkel", RoleRecordingEngineer)
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	var s2 RecordSession
+	require.NoError(t, json.Unmarshal(data, &s2))
+	assert.Equal(t, s, &s2)
+}
+
+func TestRecordSessions(t *testing.T) {
+	r := NewRecord()
+	s := NewRecordSession()
+	s.ActorRoles.Add("Geoff Emerick", RoleRecordingEngineer)
+	r.AddSession(s)
+	r.AddSession(NewRecordSession())
+	r.Clean()
+	assert.Len(t, r.Sessions, 1)
+	assert.True(t, r.SessionRoles().HasRole("Geoff Emerick", RoleRecordingEngineer))
+	assert.False(t, r.IsLive())
+	r.Sessions[0].Origin = ReleaseOriginLive
+	assert.True(t, r.IsLive())
+}
+
+func TestReleaseInferLiveOrigin(t *testing.T) {
+	r := NewRelease()
+	for i := 0; i < 2; i++ {
+		track := NewTrack()
+		track.Record.AddSession(liveSession(t, "Royal Albert Hall", "London", "GB"))
+		r.Tracks = append(r.Tracks, track)
+	}
+	r.Tracks = append(r.Tracks, NewTrack())
+	venue, ok := r.InferLiveOrigin()
+	assert.True(t, ok)
+	assert.Equal(t, "Royal Albert Hall, London, GB", venue)
+	assert.Equal(t, ReleaseOriginLive, r.ReleaseOrigin)
+
+	studio := NewTrack()
+	studio.Record.AddSession(NewRecordSession())
+	r.Tracks = append(r.Tracks, studio)
+	r.ReleaseOrigin = 0
+	_, ok = r.InferLiveOrigin()
+	assert.False(t, ok)
+	assert.Zero(t, r.ReleaseOrigin)
+
+	_, ok = NewRelease().InferLiveOrigin()
+	assert.False(t, ok)
+}
@@ -22,10 +22,12 @@ type Suggestion struct {
 }
 
 // SuggestionSet объединяет несколько результатов и оптимизирует размер за счет
-// выноса сведений об акторах в отдельную сущность.
+// выноса сведений об акторах и данных изображений в отдельные сущности.
 type SuggestionSet struct {
-	Suggestions []*Suggestion `json:"suggestions"`
-	Actors      ActorsIDs     `json:"actors,omitempty"`
+	Suggestions []*Suggestion     `json:"suggestions"`
+	Pictures    []*PictureInAudio `json:"pictures,omitempty"`
+	Actors      ActorsIDs         `json:"actors,omitempty"`
+	ActorsInfo  ActorsInfo        `json:"actors_info,omitempty"`
 }
 
 // NewSuggestion ..
@@ -37,26 +39,45 @@ func NewSuggestion() *Suggestion {
 func NewSuggestionSet() *SuggestionSet {
 	return &SuggestionSet{
 		Suggestions: []*Suggestion{},
-		Actors:      ActorsIDs{}}
+		Actors:      ActorsIDs{},
+		ActorsInfo:  ActorsInfo{}}
 }
 
 // Optimize оптимизирует релиз-данные для каждого результата и аггрегирует коды
-// акторов во внешних БД в поле Actors.
+// акторов во внешних БД в поле Actors, а сведения об акторах - в поле ActorsInfo.
+// Данные изображений однократно выносятся в поле Pictures, а в релизах остаются
+// ссылки на них по хешу.
 func (ss *SuggestionSet) Optimize() {
+	seen := pictureKeys(ss.Pictures)
 	for _, s := range ss.Suggestions {
 		s.Release.Optimize()
 		if s.Release == nil {
 			return
 		}
+		if ss.Actors == nil {
+			ss.Actors = ActorsIDs{}
+		}
 		for actor, ids := range s.Release.Actors {
-			if oldIDs, ok := ss.Actors[actor]; !ok {
-				ss.Actors[actor] = ids
-			} else {
-				for k, v := range ids {
-					oldIDs[k] = v
-				}
+			oldIDs, ok := ss.Actors[actor]
+			if !ok {
+				oldIDs = ActorIDs{}
+				ss.Actors[actor] = oldIDs
+			}
+			for k, v := range ids {
+				oldIDs[k] = v
 			}
 		}
 		s.Release.Actors = nil
+		if s.Release.ActorsInfo != nil {
+			if ss.ActorsInfo == nil {
+				ss.ActorsInfo = ActorsInfo{}
+			}
+			ss.ActorsInfo.Merge(s.Release.ActorsInfo)
+		}
+		s.Release.ActorsInfo = nil
+		s.Release.Pictures = hoistPictures(&ss.Pictures, s.Release.Pictures, seen)
+		if s.Release.Original != nil {
+			s.Release.Original.Pictures = hoistPictures(&ss.Pictures, s.Release.Original.Pictures, seen)
+		}
 	}
 }
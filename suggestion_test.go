@@ -21,3 +21,30 @@ func TestSuggestionSetOptimize(t *testing.T) {
 	set.Optimize()
 	set.Optimize()
 }
+
+func TestSuggestionSetOptimizeActorsInfo(t *testing.T) {
+	set := NewSuggestionSet()
+	for i := 0; i < 2; i++ {
+		s := NewSuggestion()
+		s.Release.Title = "Kind of Blue"
+		s.Release.ActorsInfo = ActorsInfo{"Miles Davis": {Aliases: []ActorName{"Davis"}}}
+		set.Suggestions = append(set.Suggestions, s)
+	}
+	set.Optimize()
+	assert.Len(t, set.ActorsInfo, 1)
+	assert.Nil(t, set.Suggestions[0].Release.ActorsInfo)
+}
+
+func TestSuggestionSetOptimizeUninitialized(t *testing.T) {
+	s := NewSuggestion()
+	s.Release.Actors["Miles Davis"] = ActorIDs{ISNI: "0000000121032683"}
+	actor := &Actor{Aliases: []ActorName{"Davis"}}
+	s.Release.ActorsInfo = ActorsInfo{"Miles Davis": actor}
+	set := &SuggestionSet{Suggestions: []*Suggestion{s}}
+	set.Optimize()
+	assert.Equal(t, "0000000121032683", set.Actors["Miles Davis"][ISNI])
+	assert.Equal(t, []ActorName{"Davis"}, set.ActorsInfo["Miles Davis"].Aliases)
+
+	set.ActorsInfo["Miles Davis"].AddAlias("Miles")
+	assert.Equal(t, []ActorName{"Davis"}, actor.Aliases)
+}
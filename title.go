@@ -0,0 +1,118 @@
+package metadata
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	titleBracketSuffix = regexp.MustCompile(`\s*(\(([^()]*)\)|\[([^\[\]]*)\])\s*$`)
+	titleDashSuffix    = regexp.MustCompile(`\s+[-–—]\s+([^-–—]+)$`)
+	titleVersionWords  = regexp.MustCompile(`(?i)\b(` +
+		`remaster(ed)?|remix(ed)?|rmx|mix|edit|version|live|edition|reissue(d)?|mono|stereo|` +
+		`acoustic|instrumental|demo|extended|unplugged|re-recorded|bonus track)\b`)
+	titleRemastered = regexp.MustCompile(`(?i)\bremaster(ed)?\b`)
+	titleRemix      = regexp.MustCompile(`(?i)\b(remix(ed)?|rmx|mix)\b`)
+	titleNotRemix   = regexp.MustCompile(`(?i)\b(original|radio|stereo|mono)\s+mix\b`)
+	titleLive       = regexp.MustCompile(`(?i)\b(live|unplugged)\b`)
+	titleReissue    = regexp.MustCompile(`(?i)\breissue(d)?\b`)
+)
+
+// TitleVersion описывает название релиза или трека, разделенное на основную часть и
+// сведения о версии ("Remastered 2011", "Live at Wembley", "Radio Edit", "Deluxe Edition").
+type TitleVersion struct {
+	Title   string
+	Version string
+	ReleaseRemake
+	ReleaseOrigin
+	ReleaseRepeat
+}
+
+// ParseTitleVersion отделяет от названия завершающие фрагменты в скобках или после тире,
+// содержащие сведения о версии, и определяет по ним признаки переиздания, ремейка и
+// происхождения записи.
+func ParseTitleVersion(title string) *TitleVersion {
+	ret := &TitleVersion{Title: strings.TrimSpace(title)}
+	var versions []string
+	for {
+		version, rest := splitTitleSuffix(ret.Title)
+		if version == "" || rest == "" || !titleVersionWords.MatchString(version) {
+			break
+		}
+		versions = append([]string{version}, versions...)
+		ret.Title = rest
+	}
+	ret.Version = strings.Join(versions, ", ")
+	switch {
+	case titleRemastered.MatchString(ret.Version):
+		ret.ReleaseRemake = ReleaseRemakeRemastered
+	// оригинальная, радио-, стерео- и моноверсии ремиксом не являются
+	case titleRemix.MatchString(titleNotRemix.ReplaceAllString(ret.Version, "")):
+		ret.ReleaseRemake = ReleaseRemakeRemix
+	}
+	if titleLive.MatchString(ret.Version) {
+		ret.ReleaseOrigin = ReleaseOriginLive
+	}
+	if titleReissue.MatchString(ret.Version) {
+		ret.ReleaseRepeat = ReleaseRepeatReissue
+	}
+	return ret
+}
+
+func splitTitleSuffix(title string) (string, string) {
+	if m := titleBracketSuffix.FindStringSubmatchIndex(title); m != nil {
+		var version string
+		if m[4] != -1 {
+			version = title[m[4]:m[5]]
+		} else {
+			version = title[m[6]:m[7]]
+		}
+		return strings.TrimSpace(version), strings.TrimSpace(title[:m[0]])
+	}
+	if m := titleDashSuffix.FindStringSubmatchIndex(title); m != nil {
+		return strings.TrimSpace(title[m[2]:m[3]]), strings.TrimSpace(title[:m[0]])
+	}
+	return "", title
+}
+
+// BaseTitle возвращает название без сведений о версии.
+func BaseTitle(title string) string {
+	return ParseTitleVersion(title).Title
+}
+
+// ParseTitle отделяет от названия релиза сведения о версии, сохраняя их в поле Edition,
+// и устанавливает незаполненные признаки ReleaseRemake, ReleaseOrigin и ReleaseRepeat.
+func (stub *ReleaseStub) ParseTitle() {
+	tv := ParseTitleVersion(stub.Title)
+	if tv.Version == "" {
+		return
+	}
+	stub.Title = tv.Title
+	stub.Edition = tv.Version
+	if stub.ReleaseRemake == 0 {
+		stub.ReleaseRemake = tv.ReleaseRemake
+	}
+	if stub.ReleaseOrigin == 0 {
+		stub.ReleaseOrigin = tv.ReleaseOrigin
+	}
+	if stub.ReleaseRepeat == 0 {
+		stub.ReleaseRepeat = tv.ReleaseRepeat
+	}
+}
+
+// ParseTitle отделяет от названия трека сведения о версии, сохраняя их в поле Version,
+// и устанавливает незаполненные признаки Remake и Origin.
+func (track *Track) ParseTitle() {
+	tv := ParseTitleVersion(track.Title)
+	if tv.Version == "" {
+		return
+	}
+	track.Title = tv.Title
+	track.Version = tv.Version
+	if track.Remake == 0 {
+		track.Remake = tv.ReleaseRemake
+	}
+	if track.Origin == 0 {
+		track.Origin = tv.ReleaseOrigin
+	}
+}
@@ -0,0 +1,73 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTitleVersion(t *testing.T) {
+	tv := ParseTitleVersion("Album (Remastered 2011)")
+	assert.Equal(t, "Album", tv.Title)
+	assert.Equal(t, "Remastered 2011", tv.Version)
+	assert.Equal(t, ReleaseRemakeRemastered, tv.ReleaseRemake)
+
+	tv = ParseTitleVersion("Song - Live at Wembley")
+	assert.Equal(t, "Song", tv.Title)
+	assert.Equal(t, "Live at Wembley", tv.Version)
+	assert.Equal(t, ReleaseOriginLive, tv.ReleaseOrigin)
+
+	tv = ParseTitleVersion("Song (Radio Edit)")
+	assert.Equal(t, "Song", tv.Title)
+	assert.Equal(t, "Radio Edit", tv.Version)
+	assert.Zero(t, tv.ReleaseRemake)
+
+	tv = ParseTitleVersion("Album (Deluxe Edition) [2009 Reissue]")
+	assert.Equal(t, "Album", tv.Title)
+	assert.Equal(t, "Deluxe Edition, 2009 Reissue", tv.Version)
+	assert.Equal(t, ReleaseRepeatReissue, tv.ReleaseRepeat)
+
+	tv = ParseTitleVersion("Song (Extended Club Mix)")
+	assert.Equal(t, ReleaseRemakeRemix, tv.ReleaseRemake)
+	for _, title := range []string{
+		"Song (Original Mix)", "Song - Stereo Mix", "Song [Mono Mix]", "Song (Radio Mix)"} {
+		tv = ParseTitleVersion(title)
+		assert.Equal(t, "Song", tv.Title)
+		assert.Zero(t, tv.ReleaseRemake, title)
+	}
+	tv = ParseTitleVersion("Song (Original Mix) [Dub Remix]")
+	assert.Equal(t, ReleaseRemakeRemix, tv.ReleaseRemake)
+
+	tv = ParseTitleVersion("Symphony No. 5 (Fate) - Part 2")
+	assert.Equal(t, "Symphony No. 5 (Fate) - Part 2", tv.Title)
+	assert.Empty(t, tv.Version)
+
+	assert.Equal(t, "(Remastered)", BaseTitle("(Remastered)"))
+}
+
+func TestReleaseStubParseTitle(t *testing.T) {
+	r := NewRelease()
+	r.Title = "Abbey Road (Remastered 2009)"
+	r.ReleaseRepeat = ReleaseRepeatRepress
+	r.ParseTitle()
+	assert.Equal(t, "Abbey Road", r.Title)
+	assert.Equal(t, "Remastered 2009", r.Edition)
+	assert.Equal(t, ReleaseRemakeRemastered, r.ReleaseRemake)
+	assert.Equal(t, ReleaseRepeatRepress, r.ReleaseRepeat)
+}
+
+func TestTrackParseTitle(t *testing.T) {
+	track := NewTrack()
+	track.Title = "Song - Live at Wembley"
+	track.ParseTitle()
+	assert.Equal(t, "Song", track.Title)
+	assert.Equal(t, "Live at Wembley", track.Version)
+	assert.Equal(t, ReleaseOriginLive, track.Origin)
+}
+
+func TestTitleCompare(t *testing.T) {
+	t1, t2 := NewTrack(), NewTrack()
+	t1.Title = "X"
+	t2.Title = "X (Remastered)"
+	assert.Equal(t, 1., t1.Compare(t2))
+}
@@ -3,8 +3,6 @@ package metadata
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
-	"unicode"
 	"unicode/utf8"
 
 	collection "github.com/ytsiuryn/go-collection"
@@ -35,7 +33,18 @@ func (tid TrackID) String() string {
 	case MusicbrainzTrackID:
 		return "musicbrainz_track_id"
 	}
-	return ""
+	return registeredIDKey(EntityTrack, uint8(tid))
+}
+
+// MarshalText позволяет использовать TrackID в качестве ключа словаря JSON.
+func (tid TrackID) MarshalText() ([]byte, error) {
+	return []byte(tid.String()), nil
+}
+
+// UnmarshalText получает TrackID из ключа словаря JSON (см. parseIDKey).
+func (tid *TrackID) UnmarshalText(b []byte) error {
+	*tid = TrackID(parseIDKey(EntityTrack, string(b)))
+	return nil
 }
 
 // TrackIDs представляет словарь идентификаторов трека во внешних БД.
@@ -58,7 +67,7 @@ func (tids *TrackIDs) UnmarshalJSON(b []byte) error {
 	}
 	*tids = make(TrackIDs, len(x))
 	for k, v := range x {
-		(*tids)[StrToTrackID[k]] = v
+		(*tids)[TrackID(LookupID(EntityTrack, k))] = v
 	}
 	return nil
 }
@@ -85,11 +94,15 @@ type Track struct {
 	Record      *Record           `json:"record,omitempty"`
 	Position    string            `json:"position,omitempty"`
 	Title       string            `json:"title,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Remake      ReleaseRemake     `json:"remake,omitempty"`
+	Origin      ReleaseOrigin     `json:"origin,omitempty"`
 	Notes       string            `json:"notes,omitempty"`
 	Duration    intutils.Duration `json:"duration,omitempty"` // TODO: aggregate release track Actors?
 	Actors      ActorsIDs         `json:"actors,omitempty"`
 	ActorRoles  ActorRoles        `json:"actor_roles,omitempty"`
-	IDs         collection.StrMap `json:"ids,omitempty"`
+	Featuring   []ActorName       `json:"featuring,omitempty"` // порядок упоминания feat.
+	IDs         TrackIDs          `json:"ids,omitempty"`
 	Unprocessed collection.StrMap `json:"unprocessed,omitempty"`
 	*FileInfo   `json:"file_info,omitempty"`
 	*AudioInfo  `json:"audio_info,omitempty"`
@@ -100,22 +113,7 @@ type Track struct {
 // LP ("A,B,C,D,.."): https://api.discogs.com/releases/2373051  https://api.discogs.com/releases/10131989
 // SubIndex ex.: https://api.discogs.com/releases/13452282
 func DiscNumberByTrackPos(tpos string) int {
-	if len(tpos) == 0 {
-		return 1
-	}
-	flds := stringutils.SplitIntoRegularFieldsWithDelimiters(tpos, []rune{'-', '.'})
-	if len(flds) == 2 {
-		ret, err := strconv.Atoi(flds[0])
-		if err != nil {
-			return 1
-		}
-		return ret
-	}
-	diff := int(tpos[0] - 'A')
-	if diff < 128 {
-		return diff/2 + 1
-	}
-	return 1
+	return ParsePosition(tpos).DiscNumber()
 }
 
 // ComplexPosition ..
@@ -135,7 +133,7 @@ func NewTrack() *Track {
 		Composition: NewWork(),
 		Actors:      ActorsIDs{},
 		ActorRoles:  ActorRoles{},
-		IDs:         make(map[string]string),
+		IDs:         TrackIDs{},
 		Unprocessed: make(map[string]string),
 		FileInfo:    &FileInfo{},
 		AudioInfo:   &AudioInfo{},
@@ -202,11 +200,14 @@ func (track *Track) Disc() *Disc {
 }
 
 // NormalizePosition returns a correct form of track position representation.
+// Числовые позиции дополняются ведущим нулем ("1" -> "01", "1-5" -> "1-05"), позиции
+// сторон носителя и отдельных носителей возвращаются без изменений.
 func NormalizePosition(position string) string {
-	if len(position) == 1 && unicode.IsDigit(rune(position[0])) {
-		return "0" + position
+	tp := ParsePosition(position)
+	if tp.Prefix != "" || tp.Side != "" || tp.Number == 0 {
+		return position
 	}
-	return position
+	return tp.String()
 }
 
 // SetPosition ..
@@ -219,16 +220,61 @@ func (track *Track) SetTitle(title string) {
 	track.Title = title
 }
 
-// SetISRC is a setter for ISRC code of record.
-func (track *Track) SetISRC(isrc string) {
-	track.IDs.Add("isrc", isrc)
+// SetISRC проверяет и добавляет код ISRC записи трека (см. Record.AddISRC).
+func (track *Track) SetISRC(isrc string) error {
+	if track.Record == nil {
+		track.Record = NewRecord()
+	}
+	return track.Record.AddISRC(isrc)
+}
+
+// ISRCs возвращает коды ISRC записи трека.
+func (track *Track) ISRCs() []string {
+	if track.Record == nil {
+		return nil
+	}
+	return track.Record.ISRCs
+}
+
+// UnmarshalJSON получает трек из значения JSON.
+// Словарь идентификаторов прежнего формата с произвольными ключами переносится в
+// типизированный словарь: код ISRC сохраняется в записи трека, а неизвестные ключи и
+// некорректные коды ISRC - в Unprocessed.
+func (track *Track) UnmarshalJSON(b []byte) error {
+	type trackAlias Track
+	aux := struct {
+		*trackAlias
+		IDs map[string]string `json:"ids,omitempty"`
+	}{trackAlias: (*trackAlias)(track)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	if len(aux.IDs) == 0 {
+		return nil
+	}
+	track.IDs = make(TrackIDs, len(aux.IDs))
+	for k, v := range aux.IDs {
+		if tid := TrackID(LookupID(EntityTrack, k)); tid != 0 {
+			track.IDs[tid] = v
+			continue
+		}
+		if k == ISRC.String() && track.SetISRC(v) == nil {
+			continue
+		}
+		if track.Unprocessed == nil {
+			track.Unprocessed = collection.StrMap{}
+		}
+		track.Unprocessed[k] = v
+	}
+	return nil
 }
 
 // --- Helper functions ---
 
 // Compare a Track object with other one.
+// Названия сравниваются без сведений о версии.
 func (track *Track) Compare(other *Track) float64 {
-	return stringutils.JaroWinklerDistance(track.Title, other.Title)
+	return stringutils.JaroWinklerDistance(BaseTitle(track.Title), BaseTitle(other.Title))
 }
 
 // Clean оптимизирует структуры по занимаемой памяти.
@@ -249,8 +295,7 @@ func (track *Track) Clean() {
 	if track.ActorRoles.IsEmpty() {
 		track.ActorRoles = nil
 	}
-	track.IDs.Clean()
-	if track.IDs.IsEmpty() {
+	if len(track.IDs) == 0 {
 		track.IDs = nil
 	}
 	track.Unprocessed.Clean()
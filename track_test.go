@@ -20,6 +20,9 @@ func TestDiscNumberByTrackPos(t *testing.T) {
 		"2.10":  2,
 		"3 - 1": 3,
 		"A.2":   1,
+		"A1a":   1,
+		"C2.b":  2,
+		"CD2-3": 2,
 	}
 	for k, v := range results {
 		if DiscNumberByTrackPos(k) != v {
@@ -60,6 +63,8 @@ func TestTrackSetLyrics(t *testing.T) {
 func TestTrackNormalizePosition(t *testing.T) {
 	assert.Equal(t, "01", NormalizePosition("1"))
 	assert.Equal(t, "01", NormalizePosition("01"))
+	assert.Equal(t, "1-05", NormalizePosition("1-5"))
+	assert.Equal(t, "A1", NormalizePosition("A1"))
 }
 
 func TestTrackSetPosition(t *testing.T) {
@@ -105,3 +110,40 @@ func TestLinkWithDisc(t *testing.T) {
 	tr.LinkWithDisc(d)
 	assert.Equal(t, d.Number, tr.Disc().Number)
 }
+
+func TestTrackSetISRC(t *testing.T) {
+	track := NewTrack()
+	require.NoError(t, track.SetISRC("GB-AYE-69-00531"))
+	require.NoError(t, track.SetISRC("GBAYE6900532"))
+	assert.Error(t, track.SetISRC("GB-AYE"))
+	assert.Equal(t, []string{"GBAYE6900531", "GBAYE6900532"}, track.ISRCs())
+	assert.Empty(t, track.IDs)
+}
+
+func TestTrackUnmarshalLegacyIDs(t *testing.T) {
+	var track Track
+	require.NoError(t, json.Unmarshal([]byte(
+		`{"title":"Something","ids":{"isrc":"GBAYE6900531","musicbrainz_track_id":"123","foo":"bar"}}`),
+		&track))
+	assert.Equal(t, "Something", track.Title)
+	assert.Equal(t, TrackIDs{MusicbrainzTrackID: "123"}, track.IDs)
+	assert.Equal(t, []string{"GBAYE6900531"}, track.ISRCs())
+	assert.Equal(t, "bar", track.Unprocessed["foo"])
+
+	data, err := json.Marshal(&track)
+	require.NoError(t, err)
+	var track2 Track
+	require.NoError(t, json.Unmarshal(data, &track2))
+	assert.Equal(t, track.IDs, track2.IDs)
+	assert.Equal(t, track.ISRCs(), track2.ISRCs())
+}
+
+func TestTrackUnmarshalLegacyJunkISRC(t *testing.T) {
+	r := NewRelease()
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"title":"x","tracks":[{"title":"a","ids":{"isrc":"N/A"}}]}`), r))
+	require.Len(t, r.Tracks, 1)
+	assert.Equal(t, "a", r.Tracks[0].Title)
+	assert.Empty(t, r.Tracks[0].ISRCs())
+	assert.Equal(t, "N/A", r.Tracks[0].Unprocessed["isrc"])
+}
@@ -2,9 +2,13 @@ package metadata
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	collection "github.com/ytsiuryn/go-collection"
+	stringutils "github.com/ytsiuryn/go-stringutils"
 )
 
 // WorkID тип для перечисления идентификаторов композиции/произведения во внешних БД.
@@ -13,19 +17,38 @@ type WorkID uint8
 // Допустимые значения идентификаторов композиции/произведения во внешних БД.
 const (
 	MusicbrainzWorkID WorkID = iota + 1
+	ISWC
+	DiscogsCompositionID
 )
 
 // StrToWorkID ..
 var StrToWorkID = map[string]WorkID{
-	"musicbrainz_work_id": MusicbrainzWorkID,
+	"musicbrainz_work_id":    MusicbrainzWorkID,
+	"iswc":                   ISWC,
+	"discogs_composition_id": DiscogsCompositionID,
 }
 
 func (wid WorkID) String() string {
 	switch wid {
 	case MusicbrainzWorkID:
 		return "musicbrainz_work_id"
+	case ISWC:
+		return "iswc"
+	case DiscogsCompositionID:
+		return "discogs_composition_id"
 	}
-	return ""
+	return registeredIDKey(EntityWork, uint8(wid))
+}
+
+// MarshalText позволяет использовать WorkID в качестве ключа словаря JSON.
+func (wid WorkID) MarshalText() ([]byte, error) {
+	return []byte(wid.String()), nil
+}
+
+// UnmarshalText получает WorkID из ключа словаря JSON (см. parseIDKey).
+func (wid *WorkID) UnmarshalText(b []byte) error {
+	*wid = WorkID(parseIDKey(EntityWork, string(b)))
+	return nil
 }
 
 // WorkIDs представляет словарь идентификаторов композиции/произведения во внешних БД.
@@ -41,29 +64,171 @@ func (wids WorkIDs) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON получает словарь идентификаторов композиции/произведения из значения JSON.
+// Неизвестные ключи и некорректные коды ISWC пропускаются.
 func (wids *WorkIDs) UnmarshalJSON(b []byte) error {
 	x := make(map[string]string)
 	if err := json.Unmarshal(b, &x); err != nil {
 		return err
 	}
-	*wids = make(WorkIDs, len(x))
+	*wids, _ = decodeWorkIDs(x)
+	return nil
+}
+
+// decodeWorkIDs разбирает словарь идентификаторов с произвольными ключами. Коды ISWC
+// нормализуются. Неизвестные ключи и некорректные коды ISWC возвращаются отдельно.
+func decodeWorkIDs(x map[string]string) (WorkIDs, collection.StrMap) {
+	ids := make(WorkIDs, len(x))
+	var unprocessed collection.StrMap
 	for k, v := range x {
-		(*wids)[StrToWorkID[k]] = v
+		wid := WorkID(LookupID(EntityWork, k))
+		ok := wid != 0
+		if ok && wid == ISWC {
+			var err error
+			if v, err = NormalizeISWC(v); err != nil {
+				ok = false
+				v = x[k]
+			}
+		}
+		if !ok {
+			if unprocessed == nil {
+				unprocessed = collection.StrMap{}
+			}
+			unprocessed[k] = v
+			continue
+		}
+		ids[wid] = v
+	}
+	return ids, unprocessed
+}
+
+// IsEmpty проверяет словарь на пустоту.
+func (wids WorkIDs) IsEmpty() bool {
+	return len(wids) == 0
+}
+
+// Clean удаляет пустые значения идентификаторов.
+func (wids WorkIDs) Clean() {
+	for k, v := range wids {
+		if v == "" {
+			delete(wids, k)
+		}
+	}
+}
+
+// NormalizeISWC проверяет контрольную цифру кода ISWC (ISO 15707) и возвращает его
+// в виде "T" и 10 цифр без разделителей.
+func NormalizeISWC(iswc string) (string, error) {
+	iswc = strings.ToUpper(strings.Join(strings.Fields(iswc), ""))
+	iswc = strings.TrimPrefix(iswc, "ISWC")
+	iswc = strings.NewReplacer("-", "", ".", "").Replace(iswc)
+	if len(iswc) != 11 || iswc[0] != 'T' {
+		return "", fmt.Errorf("ISWC %q: wrong format", iswc)
+	}
+	sum := 1
+	for i, r := range iswc[1:] {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("ISWC %q: wrong character %q", iswc, r)
+		}
+		if i < 9 {
+			sum += (i + 1) * int(r-'0')
+		}
+	}
+	if check := (10 - sum%10) % 10; int(iswc[10]-'0') != check {
+		return "", fmt.Errorf("ISWC %q: wrong check digit", iswc)
+	}
+	return iswc, nil
+}
+
+// FormatISWC возвращает код ISWC в каноническом виде "T-034.524.680-1".
+func FormatISWC(iswc string) string {
+	iswc, err := NormalizeISWC(iswc)
+	if err != nil {
+		return ""
+	}
+	return "T-" + iswc[1:4] + "." + iswc[4:7] + "." + iswc[7:10] + "-" + iswc[10:]
+}
+
+// WorkRelationType тип связи произведения с другим произведением.
+type WorkRelationType int8
+
+// Допустимые значения типа связи произведений.
+const (
+	WorkRelationArrangementOf WorkRelationType = iota + 1
+	WorkRelationOrchestrationOf
+	WorkRelationBasedOn
+	WorkRelationRevisionOf
+	WorkRelationTranslationOf
+	WorkRelationMedleyOf
+)
+
+// StrToWorkRelationType ..
+var StrToWorkRelationType = map[string]WorkRelationType{
+	"arrangement_of":   WorkRelationArrangementOf,
+	"orchestration_of": WorkRelationOrchestrationOf,
+	"based_on":         WorkRelationBasedOn,
+	"revision_of":      WorkRelationRevisionOf,
+	"translation_of":   WorkRelationTranslationOf,
+	"medley_of":        WorkRelationMedleyOf,
+}
+
+func (wrt WorkRelationType) String() string {
+	switch wrt {
+	case WorkRelationArrangementOf:
+		return "arrangement_of"
+	case WorkRelationOrchestrationOf:
+		return "orchestration_of"
+	case WorkRelationBasedOn:
+		return "based_on"
+	case WorkRelationRevisionOf:
+		return "revision_of"
+	case WorkRelationTranslationOf:
+		return "translation_of"
+	case WorkRelationMedleyOf:
+		return "medley_of"
+	}
+	return ""
+}
+
+// MarshalJSON ..
+func (wrt WorkRelationType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(wrt.String())
+}
+
+// UnmarshalJSON ..
+func (wrt *WorkRelationType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
 	}
+	*wrt = StrToWorkRelationType[s]
 	return nil
 }
 
+// WorkRelation описывает связь произведения с другим произведением ("arrangement of",
+// "based on"). Связанное произведение задается ссылкой: названием и идентификаторами.
+type WorkRelation struct {
+	Type  WorkRelationType `json:"type"`
+	Title string           `json:"title,omitempty"`
+	IDs   WorkIDs          `json:"ids,omitempty"`
+}
+
 // Work это часть произведения (композиция) или произведение целиком.
 // Для высокоуровневых данных Position может применяться в дилогиях, трилогиях и т.д.
 type Work struct {
-	Parent     *Work             `json:"-"`
-	Title      string            `json:"title,omitempty"`
-	Position   int               `json:"index,omitempty"`
-	Actors     ActorsIDs         `json:"actors,omitempty"`
-	ActorRoles ActorRoles        `json:"actor_roles,omitempty"`
-	Notes      string            `json:"notes,omitempty"`
-	Lyrics     *Lyrics           `json:"lyrics,omitempty"`
-	IDs        collection.StrMap `json:"ids,omitempty"` // ISWC
+	Parent      *Work              `json:"-"`
+	Title       string             `json:"title,omitempty"`
+	Position    int                `json:"index,omitempty"`
+	Catalogue   []*CatalogueNumber `json:"catalogue,omitempty"`
+	Key         string             `json:"key,omitempty"`
+	Tonality    Tonality           `json:"tonality,omitempty"`
+	Parts       []*Work            `json:"parts,omitempty"`
+	Actors      ActorsIDs          `json:"actors,omitempty"`
+	ActorRoles  ActorRoles         `json:"actor_roles,omitempty"`
+	Notes       string             `json:"notes,omitempty"`
+	Lyrics      *Lyrics            `json:"lyrics,omitempty"`
+	IDs         WorkIDs            `json:"ids,omitempty"`
+	Relations   []*WorkRelation    `json:"relations,omitempty"`
+	Unprocessed collection.StrMap  `json:"unprocessed,omitempty"`
 }
 
 // NewWork создает новый объект Composition.
@@ -72,7 +237,7 @@ func NewWork() *Work {
 		Actors:     ActorsIDs{},
 		ActorRoles: ActorRoles{},
 		Lyrics:     NewLyrics(),
-		IDs:        map[string]string{},
+		IDs:        WorkIDs{},
 	}
 }
 
@@ -99,4 +264,211 @@ func (w *Work) Clean() {
 	if w.Lyrics.IsEmpty() {
 		w.Lyrics = nil
 	}
+	if len(w.Unprocessed) == 0 {
+		w.Unprocessed = nil
+	}
+	var parts []*Work
+	for _, part := range w.Parts {
+		part.Clean()
+		if !part.IsEmpty() {
+			parts = append(parts, part)
+		}
+	}
+	w.Parts = parts
+}
+
+// UnmarshalJSON получает произведение из значения JSON и восстанавливает ссылки частей
+// произведения на родительское произведение.
+// Неизвестные ключи словаря идентификаторов прежнего формата и некорректные коды ISWC
+// переносятся в Unprocessed.
+func (w *Work) UnmarshalJSON(b []byte) error {
+	type work Work
+	aux := struct {
+		*work
+		IDs map[string]string `json:"ids,omitempty"`
+	}{work: (*work)(w)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	if aux.IDs != nil {
+		var unprocessed collection.StrMap
+		w.IDs, unprocessed = decodeWorkIDs(aux.IDs)
+		for k, v := range unprocessed {
+			if w.Unprocessed == nil {
+				w.Unprocessed = collection.StrMap{}
+			}
+			w.Unprocessed[k] = v
+		}
+	}
+	for _, part := range w.Parts {
+		part.Parent = w
+	}
+	return nil
+}
+
+// AddMovement добавляет часть произведения с указанным названием и возвращает ее.
+// Номер части определяется порядком добавления. Номера каталога и тональность части
+// определяются по названию (см. ExtractCatalogueNumbers и ParseKey).
+func (w *Work) AddMovement(title string) *Work {
+	part := NewWork()
+	part.Parent = w
+	part.Title = title
+	part.Position = len(w.Parts) + 1
+	part.ParseTitle()
+	w.Parts = append(w.Parts, part)
+	return part
+}
+
+// Root возвращает произведение верхнего уровня.
+func (w *Work) Root() *Work {
+	for w.Parent != nil {
+		w = w.Parent
+	}
+	return w
+}
+
+// Movements возвращает упорядоченный по номеру перечень частей произведения нижнего
+// уровня (частей, не содержащих собственных частей).
+func (w *Work) Movements() []*Work {
+	var ret []*Work
+	parts := append([]*Work{}, w.Parts...)
+	sort.SliceStable(parts, func(i, j int) bool { return parts[i].Position < parts[j].Position })
+	for _, part := range parts {
+		if len(part.Parts) == 0 {
+			ret = append(ret, part)
+		} else {
+			ret = append(ret, part.Movements()...)
+		}
+	}
+	return ret
+}
+
+// ParseTitle заполняет отсутствующие номера каталогов и тональность по названию
+// произведения.
+func (w *Work) ParseTitle() {
+	if len(w.Catalogue) == 0 {
+		w.Catalogue = ExtractCatalogueNumbers(w.Title)
+	}
+	if w.Key == "" {
+		w.Key, w.Tonality = ParseKey(w.Title)
+	}
+}
+
+// AddCatalogueNumber добавляет номер произведения в каталоге.
+func (w *Work) AddCatalogueNumber(s string) error {
+	cn, err := ParseCatalogueNumber(s)
+	if err != nil {
+		return err
+	}
+	for _, v := range w.Catalogue {
+		if v.Equal(cn) {
+			return nil
+		}
+	}
+	w.Catalogue = append(w.Catalogue, cn)
+	return nil
+}
+
+// Composers возвращает отсортированный перечень композиторов произведения или, при их
+// отсутствии, ближайшего родительского произведения.
+func (w *Work) Composers() []ActorName {
+	for ; w != nil; w = w.Parent {
+		var ret []ActorName
+		for name := range w.ActorRoles.Filter(IsComposer) {
+			ret = append(ret, name)
+		}
+		if len(ret) > 0 {
+			sort.Strings(ret)
+			return ret
+		}
+	}
+	return nil
+}
+
+// Compare сравнивает произведения и возвращает оценку их сходства от 0 до 1.
+// Если у обоих произведений есть номера в одном каталоге и известны композиторы, решающим
+// является совпадение номеров и композиторов (обозначения каталогов, например "K." или
+// "S.", у разных композиторов совпадают). Иначе сравниваются названия без сведений
+// о версии.
+func (w *Work) Compare(other *Work) float64 {
+	if matched, comparable := w.compareCatalogue(other); comparable {
+		if same, known := w.compareComposers(other); known {
+			if !matched || !same {
+				return 0
+			}
+			return 1
+		}
+	}
+	return stringutils.JaroWinklerDistance(BaseTitle(w.Title), BaseTitle(other.Title))
+}
+
+func (w *Work) compareCatalogue(other *Work) (matched, comparable bool) {
+	for _, cn := range w.Catalogue {
+		for _, ocn := range other.Catalogue {
+			if cn.Catalogue != ocn.Catalogue {
+				continue
+			}
+			comparable = true
+			if cn.Equal(ocn) {
+				return true, true
+			}
+		}
+	}
+	return false, comparable
+}
+
+// compareComposers проверяет наличие общего композитора. known сообщает, известны ли
+// композиторы обоих произведений.
+func (w *Work) compareComposers(other *Work) (same, known bool) {
+	composers, others := w.Composers(), other.Composers()
+	if len(composers) == 0 || len(others) == 0 {
+		return false, false
+	}
+	for _, name := range composers {
+		for _, oname := range others {
+			if SameActor(name, oname) {
+				return true, true
+			}
+		}
+	}
+	return false, true
+}
+
+// SetISWC проверяет и сохраняет код ISWC произведения.
+func (w *Work) SetISWC(iswc string) error {
+	iswc, err := NormalizeISWC(iswc)
+	if err != nil {
+		return err
+	}
+	if w.IDs == nil {
+		w.IDs = WorkIDs{}
+	}
+	w.IDs[ISWC] = iswc
+	return nil
+}
+
+// AddRelation добавляет связь произведения с другим произведением.
+// Повторное добавление связи того же типа с тем же произведением игнорируется.
+func (w *Work) AddRelation(typ WorkRelationType, other *Work) {
+	rel := &WorkRelation{Type: typ, Title: other.Title, IDs: WorkIDs{}}
+	for k, v := range other.IDs {
+		rel.IDs[k] = v
+	}
+	for _, r := range w.Relations {
+		if r.Type == typ && reflect.DeepEqual(r, rel) {
+			return
+		}
+	}
+	w.Relations = append(w.Relations, rel)
+}
+
+// RelatedWorks возвращает связи произведения указанного типа.
+func (w *Work) RelatedWorks(typ WorkRelationType) []*WorkRelation {
+	var ret []*WorkRelation
+	for _, r := range w.Relations {
+		if r.Type == typ {
+			ret = append(ret, r)
+		}
+	}
+	return ret
 }
@@ -35,3 +35,193 @@ func TestWorkIDsUnmarshal(t *testing.T) {
 	require.NoError(t, json.Unmarshal(jsonData, &m))
 	assert.Contains(t, m, MusicbrainzWorkID)
 }
+
+func TestWorkUnmarshalLegacyIDs(t *testing.T) {
+	var w Work
+	require.NoError(t, json.Unmarshal([]byte(`{"title":"Yesterday","ids":{`+
+		`"iswc":"T-034.524.680-1","musicbrainz_work_id":"123","foo":"1","bar":"2"}}`), &w))
+	assert.Equal(t, WorkIDs{ISWC: "T0345246801", MusicbrainzWorkID: "123"}, w.IDs)
+	assert.Equal(t, "1", w.Unprocessed["foo"])
+	assert.Equal(t, "2", w.Unprocessed["bar"])
+
+	w = Work{}
+	require.NoError(t, json.Unmarshal([]byte(`{"title":"x","ids":{"iswc":"N/A"}}`), &w))
+	assert.Empty(t, w.IDs)
+	assert.Equal(t, "N/A", w.Unprocessed["iswc"])
+}
+
+func TestParseCatalogueNumber(t *testing.T) {
+	results := map[string]string{
+		"Op. 27 No. 2":  "Op. 27 No. 2",
+		"op.27, no.2":   "Op. 27 No. 2",
+		"Opus 111":      "Op. 111",
+		"KV 331":        "K. 331",
+		"K.331":         "K. 331",
+		"BWV 1007":      "BWV 1007",
+		"bwv 988":       "BWV 988",
+		"Hob. xvi:52":   "Hob. XVI:52",
+		"D. 960":        "D. 960",
+		"WoO 59":        "WoO 59",
+		"RV 269":        "RV 269",
+		"Op. 10 Nr. 3a": "Op. 10 No. 3a",
+	}
+	for s, expected := range results {
+		cn, err := ParseCatalogueNumber(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, cn.String(), s)
+	}
+	for _, s := range []string{"", "Symphony", "XYZ 12", "Op. 27 extra"} {
+		_, err := ParseCatalogueNumber(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestExtractCatalogueNumbers(t *testing.T) {
+	cns := ExtractCatalogueNumbers("Piano Sonata No. 14 in C-sharp minor, Op. 27 No. 2")
+	require.Len(t, cns, 1)
+	assert.Equal(t, "Op. 27 No. 2", cns[0].String())
+	assert.Empty(t, ExtractCatalogueNumbers("Symphony in D major"))
+	assert.Empty(t, ExtractCatalogueNumbers("Concerto in D. 1. Allegro"))
+	assert.Empty(t, ExtractCatalogueNumbers("Sonata in d 1"))
+	assert.Empty(t, ExtractCatalogueNumbers("Vol. 2 Disc 1, s 3"))
+	cns = ExtractCatalogueNumbers("Sonata in E major, K. 380: Andante")
+	require.Len(t, cns, 1)
+	assert.Equal(t, "K. 380", cns[0].String())
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		title    string
+		key      string
+		tonality Tonality
+	}{
+		{"Symphony No. 5 in C minor, Op. 67", "C", TonalityMinor},
+		{"Piano Concerto in B-flat major", "Bb", TonalityMajor},
+		{"Piano Sonata No. 14 in C-sharp minor", "C#", TonalityMinor},
+		{"Messe in h-moll", "B", TonalityMinor},
+		{"Goldberg Variations", "", 0},
+		{"Sinfonie B-Dur", "Bb", TonalityMajor},
+		{"Messe in H-Moll", "B", TonalityMinor},
+		{"Tales of a Minor Prophet", "", 0},
+		{"Prelude en d minor", "D", TonalityMinor},
+		{"Concerto in e minor", "E", TonalityMinor},
+		{"Prelude in D. 1. Allegro", "", 0},
+	}
+	for _, tc := range tests {
+		key, tonality := ParseKey(tc.title)
+		assert.Equal(t, tc.key, key, tc.title)
+		assert.Equal(t, tc.tonality, tonality, tc.title)
+	}
+}
+
+func TestWorkTree(t *testing.T) {
+	w := NewWork()
+	w.Title = "Piano Sonata No. 14 in C-sharp minor, Op. 27 No. 2"
+	w.ParseTitle()
+	assert.Equal(t, "C#", w.Key)
+	assert.Equal(t, TonalityMinor, w.Tonality)
+	require.Len(t, w.Catalogue, 1)
+	m1 := w.AddMovement("I. Adagio sostenuto")
+	m2 := w.AddMovement("II. Allegretto")
+	m3 := w.AddMovement("III. Presto agitato")
+	assert.Equal(t, 3, m3.Position)
+	assert.Equal(t, w, m2.Root())
+	assert.Equal(t, []*Work{m1, m2, m3}, w.Movements())
+
+	data, err := json.Marshal(w)
+	require.NoError(t, err)
+	var w2 Work
+	require.NoError(t, json.Unmarshal(data, &w2))
+	require.Len(t, w2.Parts, 3)
+	assert.Equal(t, &w2, w2.Parts[1].Root())
+	assert.Equal(t, "Op. 27 No. 2", w2.Catalogue[0].String())
+}
+
+func TestWorkCompare(t *testing.T) {
+	w1 := NewWork()
+	w1.Title = "Moonlight Sonata"
+	w1.ActorRoles.Add("Ludwig van Beethoven", RoleComposer)
+	require.NoError(t, w1.AddCatalogueNumber("Op. 27 No. 2"))
+	w2 := NewWork()
+	w2.Title = "Sonata quasi una fantasia"
+	w2.ActorRoles.Add("Beethoven, Ludwig van", RoleComposer)
+	require.NoError(t, w2.AddCatalogueNumber("op.27, no.2"))
+	assert.Equal(t, 1., w1.Compare(w2))
+
+	w2.Catalogue = nil
+	require.NoError(t, w2.AddCatalogueNumber("Op. 27 No. 1"))
+	assert.Equal(t, 0., w1.Compare(w2))
+
+	w3 := NewWork()
+	w3.Title = "Moonlight Sonata"
+	w3.ActorRoles.Add("Frédéric Chopin", RoleComposer)
+	require.NoError(t, w3.AddCatalogueNumber("Op. 27 No. 2"))
+	assert.Equal(t, 0., w1.Compare(w3))
+
+	w4 := NewWork()
+	w4.Title = "Moonlight Sonata"
+	assert.Equal(t, 1., w1.Compare(w4))
+
+	// без композитора совпадение номера в каталоге не является решающим
+	mozart := NewWork()
+	mozart.Title = "Piano Sonata No. 11"
+	mozart.ActorRoles.Add("Wolfgang Amadeus Mozart", RoleComposer)
+	require.NoError(t, mozart.AddCatalogueNumber("K. 331"))
+	scarlatti := NewWork()
+	scarlatti.Title = "Sonata in D minor"
+	require.NoError(t, scarlatti.AddCatalogueNumber("K. 331"))
+	assert.Less(t, mozart.Compare(scarlatti), 1.)
+	assert.Equal(t, mozart.Compare(scarlatti), scarlatti.Compare(mozart))
+}
+
+func TestNormalizeISWC(t *testing.T) {
+	for _, s := range []string{"T-034.524.680-1", "T0345246801", "iswc t 034 524 680 1"} {
+		iswc, err := NormalizeISWC(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, "T0345246801", iswc, s)
+	}
+	assert.Equal(t, "T-034.524.680-1", FormatISWC("T0345246801"))
+	for _, s := range []string{"T-034.524.680-2", "T-034.524.680", "X0345246801", "T03452X6801"} {
+		_, err := NormalizeISWC(s)
+		assert.Error(t, err, s)
+	}
+	assert.Equal(t, "", FormatISWC("T-034.524.680-2"))
+}
+
+func TestWorkSetISWC(t *testing.T) {
+	w := NewWork()
+	require.NoError(t, w.SetISWC("T-034.524.680-1"))
+	assert.Equal(t, "T0345246801", w.IDs[ISWC])
+	assert.Error(t, w.SetISWC("T-034.524.680-9"))
+	w.IDs[DiscogsCompositionID] = "1234"
+	data, err := json.Marshal(w.IDs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"iswc":"T0345246801","discogs_composition_id":"1234"}`, string(data))
+
+	var decoded Work
+	require.NoError(t, json.Unmarshal([]byte(`{"title":"Yesterday"}`), &decoded))
+	require.NoError(t, decoded.SetISWC("T0345246801"))
+	assert.Equal(t, "T0345246801", decoded.IDs[ISWC])
+}
+
+func TestWorkRelations(t *testing.T) {
+	original := NewWork()
+	original.Title = "Pictures at an Exhibition"
+	original.IDs[MusicbrainzWorkID] = "a1b2"
+	arr := NewWork()
+	arr.Title = "Pictures at an Exhibition (orch. Ravel)"
+	arr.AddRelation(WorkRelationOrchestrationOf, original)
+	arr.AddRelation(WorkRelationOrchestrationOf, original)
+	arr.AddRelation(WorkRelationBasedOn, original)
+	assert.Len(t, arr.Relations, 2)
+	rels := arr.RelatedWorks(WorkRelationOrchestrationOf)
+	require.Len(t, rels, 1)
+	assert.Equal(t, "a1b2", rels[0].IDs[MusicbrainzWorkID])
+	assert.Empty(t, arr.RelatedWorks(WorkRelationArrangementOf))
+
+	data, err := json.Marshal(arr.Relations)
+	require.NoError(t, err)
+	var relations []*WorkRelation
+	require.NoError(t, json.Unmarshal(data, &relations))
+	assert.Equal(t, arr.Relations, relations)
+}